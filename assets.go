@@ -0,0 +1,36 @@
+// Package goanxio embeds the default templates, static files and content of go.anx.io into the binary, files
+// in the directories given on the command line take precedence over them.
+package goanxio
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed templates static content
+var assets embed.FS
+
+// Templates returns the default templates.
+func Templates() fs.FS {
+	return sub("templates")
+}
+
+// Static returns the default static files.
+func Static() fs.FS {
+	return sub("static")
+}
+
+// Content returns the default content files.
+func Content() fs.FS {
+	return sub("content")
+}
+
+func sub(dir string) fs.FS {
+	ret, err := fs.Sub(assets, dir)
+	if err != nil {
+		// cannot happen for the directories embedded above
+		panic(err)
+	}
+
+	return ret
+}
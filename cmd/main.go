@@ -4,21 +4,30 @@ import (
 	"bytes"
 	"flag"
 	"fmt"
-	"io"
 	"io/fs"
 	"log"
+	"mime"
 	"net/http"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
+	goanxio "github.com/anexia-it/go.anx.io"
+	"github.com/anexia-it/go.anx.io/pkg/compression"
 	"github.com/anexia-it/go.anx.io/pkg/config"
+	"github.com/anexia-it/go.anx.io/pkg/markdown"
+	"github.com/anexia-it/go.anx.io/pkg/output"
+	"github.com/anexia-it/go.anx.io/pkg/overlay"
 	"github.com/anexia-it/go.anx.io/pkg/render"
 	"github.com/anexia-it/go.anx.io/pkg/source"
 	"github.com/anexia-it/go.anx.io/pkg/types"
 )
 
+// immutableCacheControl is sent for fingerprinted files, which never change.
+const immutableCacheControl = "public, max-age=31536000, immutable"
+
 var version = "dev"
 var sourceURL = ""
 
@@ -26,11 +35,17 @@ var (
 	mode            = "generate"
 	configFile      = "packages.yaml"
 	templateDirPath = ""
+	themeDirPath    = ""
 	contentPath     = ""
 	staticDirPath   = ""
 	sourceCache     = "source-cache"
 	listenAddress   = "localhost:1312"
 	destinationPath = "public"
+	baseURL         = "https://go.anx.io"
+	precompress     = false
+	prune           = false
+	codeStyle       = markdown.DefaultCodeStyle
+	darkCodeStyle   = markdown.DefaultDarkCodeStyle
 )
 
 func main() {
@@ -44,12 +59,18 @@ func main() {
 
 	flag.StringVar(&mode, "mode", mode, "Mode to run this into (generate|serve)")
 	flag.StringVar(&configFile, "config-file", configFile, "Path to config file to use")
-	flag.StringVar(&templateDirPath, "template-directory", templateDirPath, "Path to directory containing the templates")
-	flag.StringVar(&contentPath, "content-directory", contentPath, "Path to directory containing the content files")
-	flag.StringVar(&staticDirPath, "static-directory", staticDirPath, "Path to directory containing the static files")
+	flag.StringVar(&templateDirPath, "template-directory", templateDirPath, "Path to directory containing templates overriding the embedded ones")
+	flag.StringVar(&themeDirPath, "theme-directory", themeDirPath, "Path to directory containing a theme, overriding the embedded templates and being overridden by the template directory")
+	flag.StringVar(&contentPath, "content-directory", contentPath, "Path to directory containing content files overriding the embedded ones")
+	flag.StringVar(&staticDirPath, "static-directory", staticDirPath, "Path to directory containing static files overriding the embedded ones")
 	flag.StringVar(&sourceCache, "source-cache", sourceCache, "Path to where to cache sources")
 	flag.StringVar(&listenAddress, "listen-address", listenAddress, "Address to listen on in serve mode")
 	flag.StringVar(&destinationPath, destinationPath, "public", "Path to directory to store the generated files in")
+	flag.StringVar(&baseURL, "base-url", baseURL, "URL the generated site is published at")
+	flag.StringVar(&codeStyle, "code-style", codeStyle, "Chroma style for highlighting code")
+	flag.StringVar(&darkCodeStyle, "dark-code-style", darkCodeStyle, "Chroma style for highlighting code in dark mode, empty to disable")
+	flag.BoolVar(&precompress, "precompress", precompress, "Write gzip compressed siblings of generated text files")
+	flag.BoolVar(&prune, "prune", prune, "Remove files in the destination directory not generated again")
 
 	flag.Parse()
 
@@ -58,6 +79,8 @@ func main() {
 		return
 	}
 
+	checkDirectoryFlags()
+
 	packages, err := config.Load(configFile)
 	if err != nil {
 		log.Fatalf("Error loading config file: %v", err)
@@ -72,23 +95,95 @@ func main() {
 		log.Fatalf("Error loading sources: %v", err)
 	}
 
-	renderer, err := render.NewRenderer(templateDirPath, contentPath, packages)
+	staticFiles := layered(goanxio.Static(), staticDirPath)
+
+	renderer, err := render.NewRenderer(layered(goanxio.Templates(), templateDirPath, themeDirPath), layered(goanxio.Content(), contentPath), packages)
 	if err != nil {
 		log.Fatalf("Error initializing Renderer: %v", err)
 	}
 
 	renderer.SetBuildInfo(version, sourceURL)
+	renderer.SetBaseURL(baseURL)
+	renderer.SetStaticFiles(staticFiles)
+	renderer.SetPrecompress(precompress)
+
+	if buildTime, ok := sourceDateEpoch(); ok {
+		renderer.SetBuildTime(buildTime)
+	}
+
+	if err := renderer.SetCodeStyles(codeStyle, darkCodeStyle); err != nil {
+		log.Fatalf("Error configuring code styles: %v", err)
+	}
 
 	switch mode {
 	case "serve":
-		runServe(packages, renderer)
+		renderer.SetAssetCaching(false)
+		runServe(packages, renderer, staticFiles)
 	case "generate":
-		runGenerate(renderer)
+		runGenerate(renderer, staticFiles)
+	}
+}
+
+// sourceDateEpoch returns the time given in the SOURCE_DATE_EPOCH environment variable, used for reproducible builds.
+// See https://reproducible-builds.org/specs/source-date-epoch/
+func sourceDateEpoch() (time.Time, bool) {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		return time.Time{}, false
 	}
+
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		log.Fatalf("Error parsing SOURCE_DATE_EPOCH: %v", err)
+	}
+
+	return time.Unix(seconds, 0).UTC(), true
 }
 
-func runServe(packages []*types.Package, renderer *render.Renderer) {
-	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir(staticDirPath))))
+// checkDirectoryFlags exits if a directory given explicitly with a flag does not exist. Only the default
+// directories in the working directory are optional.
+func checkDirectoryFlags() {
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "template-directory", "theme-directory", "content-directory", "static-directory":
+		default:
+			return
+		}
+
+		dirPath := f.Value.String()
+		if dirPath == "" {
+			return
+		}
+
+		if stat, err := os.Stat(dirPath); err != nil {
+			log.Fatalf("Error opening directory %q given with --%v: %v", dirPath, f.Name, err)
+		} else if !stat.IsDir() {
+			log.Fatalf("Path %q given with --%v is not a directory", dirPath, f.Name)
+		}
+	})
+}
+
+// layered returns the files of the given directories layered over the embedded defaults, earlier directories
+// taking precedence. Directories not existing are skipped, checkDirectoryFlags ensures that only happens for
+// the default ones.
+func layered(defaults fs.FS, dirPaths ...string) fs.FS {
+	layers := make([]fs.FS, 0, len(dirPaths)+1)
+
+	for _, dirPath := range dirPaths {
+		if stat, err := os.Stat(dirPath); dirPath != "" && err == nil && stat.IsDir() {
+			layers = append(layers, os.DirFS(dirPath))
+		}
+	}
+
+	if len(layers) == 0 {
+		return defaults
+	}
+
+	return overlay.New(append(layers, defaults)...)
+}
+
+func runServe(packages []*types.Package, renderer *render.Renderer, staticFiles fs.FS) {
+	http.Handle("/static/", http.StripPrefix("/static/", serveStatic(staticFiles)))
 
 	for _, pkg := range packages {
 		servePackage(pkg, renderer)
@@ -96,9 +191,22 @@ func runServe(packages []*types.Package, renderer *render.Renderer) {
 
 	servePackage(nil, renderer)
 
+	http.HandleFunc("/search", func(res http.ResponseWriter, req *http.Request) {
+		buffer := bytes.Buffer{}
+		if err := renderer.RenderSearchResults(req.URL.Query().Get("q"), &buffer); err != nil {
+			log.Printf("Error rendering search results: %v", err)
+			http.Error(res, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		} else {
+			res.Header().Add("Content-Type", "application/json")
+			res.WriteHeader(http.StatusOK)
+			_, _ = res.Write(buffer.Bytes())
+		}
+	})
+
 	//nolint:exhaustruct // We only set useful things here
 	server := http.Server{
 		Addr:              listenAddress,
+		Handler:           compression.Handler(http.DefaultServeMux),
 		ReadHeaderTimeout: 30 * time.Second,
 		ReadTimeout:       30 * time.Second,
 		WriteTimeout:      30 * time.Second,
@@ -109,13 +217,23 @@ func runServe(packages []*types.Package, renderer *render.Renderer) {
 	}
 }
 
-func runGenerate(renderer *render.Renderer) {
-	if err := renderer.GenerateFiles(destinationPath); err != nil {
-		log.Fatalf("Error rendering files: %v", err)
-	}
+// runGenerate generates the site into a temporary directory, replacing the destination directory with it
+// only when everything was generated successfully.
+func runGenerate(renderer *render.Renderer, staticFiles fs.FS) {
+	err := output.Write(destinationPath, prune, func(dir string) error {
+		if err := renderer.GenerateFiles(dir); err != nil {
+			return fmt.Errorf("error rendering files: %w", err)
+		}
 
-	if err := copyStaticFiles(staticDirPath, destinationPath); err != nil {
-		log.Fatalf("Error copying static files: %v", err)
+		if err := copyStaticFiles(staticFiles, dir); err != nil {
+			return fmt.Errorf("error copying static files: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Fatalf("Error generating files: %v", err)
 	}
 }
 
@@ -129,15 +247,22 @@ func servePackage(pkg *types.Package, renderer *render.Renderer) {
 	http.HandleFunc(basePath, func(res http.ResponseWriter, req *http.Request) {
 		filePath := strings.TrimPrefix(req.URL.Path, basePath)
 
+		renderFunc := renderer.RenderFile
+		if pkg != nil && req.URL.Query().Get("go-get") == "1" {
+			renderFunc = renderer.RenderGoImport
+		}
+
 		buffer := bytes.Buffer{}
-		if err := renderer.RenderFile(pkg, filePath, &buffer); err != nil {
-			res.WriteHeader(http.StatusInternalServerError)
-			_, _ = res.Write([]byte(err.Error()))
+		if err := renderFunc(pkg, filePath, &buffer); render.IsNotFound(err) {
+			serveNotFound(res, pkg, filePath, renderer)
+		} else if err != nil {
+			log.Printf("Error rendering %q: %v", req.URL.Path, err)
+			http.Error(res, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		} else {
-			if strings.HasSuffix(filePath, ".css") {
-				res.Header().Add("Content-Type", "text/css; charset=utf-8")
-			} else {
-				res.Header().Add("Content-Type", "text/html; charset=utf-8")
+			res.Header().Add("Content-Type", contentTypeForFile(filePath))
+
+			if _, ok := render.StripFingerprint(filePath); ok {
+				res.Header().Add("Cache-Control", immutableCacheControl)
 			}
 
 			res.WriteHeader(http.StatusOK)
@@ -146,12 +271,85 @@ func servePackage(pkg *types.Package, renderer *render.Renderer) {
 	})
 }
 
-func copyStaticFiles(staticPath string, destinationPath string) error {
-	staticFiles := os.DirFS(staticPath)
+// serveStatic serves the static files, fingerprinted ones with headers allowing to cache them forever.
+func serveStatic(staticFiles fs.FS) http.Handler {
+	fileServer := http.FileServer(http.FS(staticFiles))
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		original, fingerprinted := render.StripFingerprint(req.URL.Path)
+
+		contents, err := fs.ReadFile(staticFiles, original)
+		if err != nil && !fingerprinted {
+			fileServer.ServeHTTP(res, req)
+			return
+		} else if err != nil || (fingerprinted && render.Fingerprint(original, contents) != req.URL.Path) {
+			http.NotFound(res, req)
+			return
+		}
+
+		if fingerprinted {
+			res.Header().Add("Cache-Control", immutableCacheControl)
+		}
+
+		res.Header().Set("Vary", "Accept-Encoding")
+
+		// serve precompressed siblings if the client accepts them, ranges refer to the uncompressed contents
+		compressed, err := fs.ReadFile(staticFiles, original+compression.Extension)
+		if err == nil && compression.AcceptsGzip(req) && req.Header.Get("Range") == "" {
+			contentType := mime.TypeByExtension(path.Ext(original))
+			if contentType == "" {
+				contentType = http.DetectContentType(contents)
+			}
+
+			res.Header().Set("Content-Type", contentType)
+			res.Header().Set("Content-Encoding", "gzip")
+
+			contents = compressed
+		}
+
+		http.ServeContent(res, req, original, time.Time{}, bytes.NewReader(contents))
+	})
+}
+
+func serveNotFound(res http.ResponseWriter, pkg *types.Package, filePath string, renderer *render.Renderer) {
+	buffer := bytes.Buffer{}
+	if err := renderer.RenderNotFound(pkg, filePath, &buffer); err != nil {
+		log.Printf("Error rendering not found page: %v", err)
+		http.Error(res, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+
+		return
+	}
+
+	res.Header().Add("Content-Type", "text/html; charset=utf-8")
+	res.WriteHeader(http.StatusNotFound)
+	_, _ = res.Write(buffer.Bytes())
+}
 
+// contentTypeForFile returns the content type for a rendered file, which is HTML except for
+// some special files like stylesheets.
+func contentTypeForFile(filePath string) string {
+	switch path.Ext(filePath) {
+	case ".css":
+		return "text/css; charset=utf-8"
+	case ".svg":
+		return "image/svg+xml"
+	case ".json":
+		return "application/json"
+	case ".atom":
+		return "application/atom+xml; charset=utf-8"
+	case ".xml":
+		return "application/xml; charset=utf-8"
+	case ".txt":
+		return "text/plain; charset=utf-8"
+	default:
+		return "text/html; charset=utf-8"
+	}
+}
+
+func copyStaticFiles(staticFiles fs.FS, destinationPath string) error {
 	err := fs.WalkDir(staticFiles, ".", func(walkEntry string, d fs.DirEntry, err error) error {
 		if err != nil {
-			log.Fatalf("Error walking static files directory: %v", err)
+			return err
 		}
 
 		if d.IsDir() {
@@ -163,20 +361,25 @@ func copyStaticFiles(staticPath string, destinationPath string) error {
 			return nil
 		}
 
-		sourceStream, err := os.Open(path.Join(staticPath, walkEntry))
+		contents, err := fs.ReadFile(staticFiles, walkEntry)
 		if err != nil {
-			return fmt.Errorf("error opening source file %q: %w", staticPath, err)
+			return fmt.Errorf("error reading source file %q: %w", walkEntry, err)
 		}
 
-		//nolint:nosnakecase // O_WRONLY, O_CREATE and O_TRUNC are defined by the os package (and underlying POSIX spec).
-		destinationStream, err := os.OpenFile(path.Join(destinationPath, "static", walkEntry), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
-		if err != nil {
-			return fmt.Errorf("error opening destination file %q: %w", destinationPath, err)
-		}
+		// we write the file both with and without fingerprint, templates use the fingerprinted one while other
+		// sites might link to the plain one
+		for _, name := range []string{walkEntry, render.Fingerprint(walkEntry, contents)} {
+			destination := path.Join(destinationPath, "static", name)
 
-		_, err = io.Copy(destinationStream, sourceStream)
-		if err != nil {
-			return fmt.Errorf("error copying file data: %w", err)
+			if err := os.WriteFile(destination, contents, 0644); err != nil {
+				return fmt.Errorf("error writing destination file %q: %w", destination, err)
+			}
+
+			if precompress {
+				if err := compression.WritePrecompressed(destination, contents); err != nil {
+					return fmt.Errorf("error precompressing static file: %w", err)
+				}
+			}
 		}
 
 		return nil
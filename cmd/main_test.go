@@ -0,0 +1,89 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/anexia-it/go.anx.io/pkg/compression"
+)
+
+func TestServeStaticPrecompressed(t *testing.T) {
+	t.Parallel()
+
+	stylesheet := strings.Repeat("body { color: black; }\n", 100)
+
+	compressed, err := compression.Gzip([]byte(stylesheet))
+	if err != nil {
+		t.Fatalf("error compressing: %v", err)
+	}
+
+	handler := http.StripPrefix("/static/", serveStatic(fstest.MapFS{
+		"style.css":                         &fstest.MapFile{Data: []byte(stylesheet)},
+		"style.css" + compression.Extension: &fstest.MapFile{Data: compressed},
+		"notes.txt":                         &fstest.MapFile{Data: []byte(stylesheet)},
+	}))
+
+	testCases := []struct {
+		name         string
+		path         string
+		accept       string
+		rangeHeader  string
+		compressed   bool
+		expectedBody string
+	}{
+		{"precompressed", "/static/style.css", "gzip", "", true, stylesheet},
+		{"not accepting gzip", "/static/style.css", "", "", false, stylesheet},
+		{"range request", "/static/style.css", "gzip", "bytes=0-9", false, stylesheet[:10]},
+		{"without precompressed file", "/static/notes.txt", "gzip", "", false, stylesheet},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+			req.Header.Set("Accept-Encoding", testCase.accept)
+
+			if testCase.rangeHeader != "" {
+				req.Header.Set("Range", testCase.rangeHeader)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if vary := rec.Header().Get("Vary"); vary != "Accept-Encoding" {
+				t.Errorf("Vary header is %q, expected Accept-Encoding", vary)
+			}
+
+			var body io.Reader = rec.Body
+
+			if encoding := rec.Header().Get("Content-Encoding"); (encoding == "gzip") != testCase.compressed {
+				t.Fatalf("Content-Encoding %q did not match compressed %v", encoding, testCase.compressed)
+			} else if testCase.compressed {
+				reader, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatalf("error reading compressed body: %v", err)
+				}
+
+				body = reader
+			}
+
+			if contentType := rec.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/") {
+				t.Errorf("Content-Type %q is not the one of the uncompressed file", contentType)
+			}
+
+			if data, err := io.ReadAll(body); err != nil {
+				t.Errorf("error reading body: %v", err)
+			} else if string(data) != testCase.expectedBody {
+				t.Errorf("body %q did not match the expected one %q", data, testCase.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+// Package apidiff compares the exported API of two versions of a module, classifying every change as
+// compatible or incompatible, similar to golang.org/x/exp/apidiff but a lot less thorough.
+//
+// Since both versions are type checked independently, types are compared by their string
+// representation instead of type identity.
+package apidiff
+
+import (
+	"fmt"
+	"go/token"
+	gotypes "go/types"
+	"sort"
+	"strings"
+)
+
+// Change is a single change of the exported API.
+type Change struct {
+	// Package is the import path of the changed package.
+	Package string
+
+	// Message describes the change, e.g. `New: changed from func() to func(string)`.
+	Message string
+
+	// Compatible is true if code using the old API still compiles with the new one.
+	Compatible bool
+}
+
+// Report lists all changes to the exported API between two versions.
+type Report struct {
+	Changes []Change
+}
+
+// Incompatible returns true if at least one of the changes is incompatible.
+func (r Report) Incompatible() bool {
+	for _, change := range r.Changes {
+		if !change.Compatible {
+			return true
+		}
+	}
+
+	return false
+}
+
+type reporter struct {
+	pkg     string
+	changes []Change
+}
+
+func (r *reporter) compatible(format string, args ...interface{}) {
+	r.changes = append(r.changes, Change{Package: r.pkg, Message: fmt.Sprintf(format, args...), Compatible: true})
+}
+
+func (r *reporter) incompatible(format string, args ...interface{}) {
+	r.changes = append(r.changes, Change{Package: r.pkg, Message: fmt.Sprintf(format, args...), Compatible: false})
+}
+
+// Compare compares the exported API of the old and new packages, matched by import path. Internal
+// and main packages are ignored, as they cannot be imported by users.
+func Compare(oldPackages, newPackages []*gotypes.Package) Report {
+	oldByPath := publicPackages(oldPackages)
+	newByPath := publicPackages(newPackages)
+
+	changes := make([]Change, 0)
+
+	for importPath, oldPkg := range oldByPath {
+		rep := reporter{pkg: importPath, changes: nil}
+
+		if newPkg, ok := newByPath[importPath]; ok {
+			comparePackages(&rep, oldPkg, newPkg)
+		} else {
+			rep.incompatible("package removed")
+		}
+
+		changes = append(changes, rep.changes...)
+	}
+
+	for importPath := range newByPath {
+		if _, ok := oldByPath[importPath]; !ok {
+			changes = append(changes, Change{Package: importPath, Message: "package added", Compatible: true})
+		}
+	}
+
+	sort.SliceStable(changes, func(a, b int) bool {
+		if changes[a].Package != changes[b].Package {
+			return changes[a].Package < changes[b].Package
+		}
+
+		return changes[a].Message < changes[b].Message
+	})
+
+	return Report{Changes: changes}
+}
+
+func publicPackages(pkgs []*gotypes.Package) map[string]*gotypes.Package {
+	ret := make(map[string]*gotypes.Package, len(pkgs))
+
+	for _, pkg := range pkgs {
+		elements := strings.Split(pkg.Path(), "/")
+
+		isInternal := false
+		for _, element := range elements {
+			isInternal = isInternal || element == "internal"
+		}
+
+		if !isInternal && pkg.Name() != "main" {
+			ret[pkg.Path()] = pkg
+		}
+	}
+
+	return ret
+}
+
+func comparePackages(rep *reporter, oldPkg, newPkg *gotypes.Package) {
+	for _, name := range oldPkg.Scope().Names() {
+		oldObj := oldPkg.Scope().Lookup(name)
+		if !oldObj.Exported() {
+			continue
+		}
+
+		newObj := newPkg.Scope().Lookup(name)
+		if newObj == nil || !newObj.Exported() {
+			rep.incompatible("%v: removed", name)
+			continue
+		}
+
+		compareObjects(rep, oldObj, newObj)
+	}
+
+	for _, name := range newPkg.Scope().Names() {
+		if newObj := newPkg.Scope().Lookup(name); newObj.Exported() && oldPkg.Scope().Lookup(name) == nil {
+			rep.compatible("%v: added", name)
+		}
+	}
+}
+
+func objectKind(obj gotypes.Object) string {
+	switch obj.(type) {
+	case *gotypes.Const:
+		return "const"
+	case *gotypes.Var:
+		return "var"
+	case *gotypes.Func:
+		return "func"
+	case *gotypes.TypeName:
+		return "type"
+	default:
+		return "object"
+	}
+}
+
+func typeString(t gotypes.Type) string {
+	return gotypes.TypeString(t, func(pkg *gotypes.Package) string {
+		return pkg.Path()
+	})
+}
+
+func compareObjects(rep *reporter, oldObj, newObj gotypes.Object) {
+	name := oldObj.Name()
+
+	if objectKind(oldObj) != objectKind(newObj) {
+		rep.incompatible("%v: changed from %v to %v", name, objectKind(oldObj), objectKind(newObj))
+		return
+	}
+
+	switch oldObj := oldObj.(type) {
+	case *gotypes.Const:
+		//nolint:forcetypeassert // kind compared above
+		newConst := newObj.(*gotypes.Const)
+
+		if typeString(oldObj.Type()) != typeString(newConst.Type()) {
+			rep.incompatible("%v: type changed from %v to %v", name, typeString(oldObj.Type()), typeString(newConst.Type()))
+		} else if oldObj.Val().ExactString() != newConst.Val().ExactString() {
+			rep.incompatible("%v: value changed from %v to %v", name, oldObj.Val().ExactString(), newConst.Val().ExactString())
+		}
+	case *gotypes.TypeName:
+		//nolint:forcetypeassert // kind compared above
+		compareTypes(rep, oldObj, newObj.(*gotypes.TypeName))
+	default:
+		if oldType, newType := typeString(oldObj.Type()), typeString(newObj.Type()); oldType != newType {
+			rep.incompatible("%v: changed from %v to %v", name, oldType, newType)
+		}
+	}
+}
+
+func compareTypes(rep *reporter, oldType, newType *gotypes.TypeName) {
+	name := oldType.Name()
+
+	if oldType.IsAlias() != newType.IsAlias() {
+		rep.incompatible("%v: changed between type alias and defined type", name)
+		return
+	}
+
+	oldUnderlying := oldType.Type().Underlying()
+	newUnderlying := newType.Type().Underlying()
+
+	switch oldUnderlying := oldUnderlying.(type) {
+	case *gotypes.Struct:
+		if newStruct, ok := newUnderlying.(*gotypes.Struct); ok {
+			compareStructs(rep, name, oldUnderlying, newStruct)
+		} else {
+			rep.incompatible("%v: changed from %v to %v", name, typeString(oldUnderlying), typeString(newUnderlying))
+		}
+	case *gotypes.Interface:
+		if newInterface, ok := newUnderlying.(*gotypes.Interface); ok {
+			compareInterfaces(rep, name, oldUnderlying, newInterface)
+		} else {
+			rep.incompatible("%v: changed from %v to %v", name, typeString(oldUnderlying), typeString(newUnderlying))
+		}
+	default:
+		if typeString(oldUnderlying) != typeString(newUnderlying) {
+			rep.incompatible("%v: changed from %v to %v", name, typeString(oldUnderlying), typeString(newUnderlying))
+		}
+	}
+
+	if _, isInterface := oldUnderlying.(*gotypes.Interface); !isInterface {
+		compareMethodSets(rep, name, oldType.Type(), newType.Type())
+	}
+}
+
+func compareStructs(rep *reporter, name string, oldStruct, newStruct *gotypes.Struct) {
+	newFields := make(map[string]*gotypes.Var, newStruct.NumFields())
+
+	for i := 0; i < newStruct.NumFields(); i++ {
+		newFields[newStruct.Field(i).Name()] = newStruct.Field(i)
+	}
+
+	oldFields := make(map[string]bool, oldStruct.NumFields())
+
+	for i := 0; i < oldStruct.NumFields(); i++ {
+		oldField := oldStruct.Field(i)
+		oldFields[oldField.Name()] = true
+
+		if !oldField.Exported() {
+			continue
+		}
+
+		if newField, ok := newFields[oldField.Name()]; !ok || !newField.Exported() {
+			rep.incompatible("%v.%v: removed", name, oldField.Name())
+		} else if typeString(oldField.Type()) != typeString(newField.Type()) {
+			rep.incompatible("%v.%v: changed from %v to %v", name, oldField.Name(), typeString(oldField.Type()), typeString(newField.Type()))
+		}
+	}
+
+	for i := 0; i < newStruct.NumFields(); i++ {
+		if newField := newStruct.Field(i); newField.Exported() && !oldFields[newField.Name()] {
+			rep.compatible("%v.%v: added", name, newField.Name())
+		}
+	}
+}
+
+func compareInterfaces(rep *reporter, name string, oldInterface, newInterface *gotypes.Interface) {
+	oldMethods := methodsOfInterface(oldInterface)
+	newMethods := methodsOfInterface(newInterface)
+
+	// adding methods to an interface breaks implementations, unless they cannot exist outside of the package anyway
+	canBeImplemented := true
+
+	for methodName := range oldMethods {
+		canBeImplemented = canBeImplemented && token.IsExported(methodName)
+	}
+
+	for methodName, oldSignature := range oldMethods {
+		if newSignature, ok := newMethods[methodName]; !ok {
+			rep.incompatible("%v.%v: removed", name, methodName)
+		} else if oldSignature != newSignature {
+			rep.incompatible("%v.%v: changed from %v to %v", name, methodName, oldSignature, newSignature)
+		}
+	}
+
+	for methodName := range newMethods {
+		if _, ok := oldMethods[methodName]; ok || !token.IsExported(methodName) {
+			continue
+		}
+
+		if canBeImplemented {
+			rep.incompatible("%v.%v: added to interface", name, methodName)
+		} else {
+			rep.compatible("%v.%v: added to interface", name, methodName)
+		}
+	}
+}
+
+func methodsOfInterface(iface *gotypes.Interface) map[string]string {
+	ret := make(map[string]string, iface.NumMethods())
+
+	for i := 0; i < iface.NumMethods(); i++ {
+		ret[iface.Method(i).Name()] = typeString(iface.Method(i).Type())
+	}
+
+	return ret
+}
+
+// compareMethodSets compares the exported methods of the pointer type, which includes the
+// methods of the value type.
+func compareMethodSets(rep *reporter, name string, oldType, newType gotypes.Type) {
+	oldMethods := exportedMethods(gotypes.NewPointer(oldType))
+	newMethods := exportedMethods(gotypes.NewPointer(newType))
+
+	for methodName, oldSignature := range oldMethods {
+		if newSignature, ok := newMethods[methodName]; !ok {
+			rep.incompatible("%v.%v: removed", name, methodName)
+		} else if oldSignature != newSignature {
+			rep.incompatible("%v.%v: changed from %v to %v", name, methodName, oldSignature, newSignature)
+		}
+	}
+
+	for methodName := range newMethods {
+		if _, ok := oldMethods[methodName]; !ok {
+			rep.compatible("%v.%v: added", name, methodName)
+		}
+	}
+}
+
+func exportedMethods(t gotypes.Type) map[string]string {
+	methodSet := gotypes.NewMethodSet(t)
+	ret := make(map[string]string, methodSet.Len())
+
+	for i := 0; i < methodSet.Len(); i++ {
+		if method := methodSet.At(i).Obj(); method.Exported() {
+			ret[method.Name()] = typeString(method.Type())
+		}
+	}
+
+	return ret
+}
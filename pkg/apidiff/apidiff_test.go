@@ -0,0 +1,101 @@
+package apidiff_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"testing"
+
+	"github.com/anexia-it/go.anx.io/pkg/apidiff"
+)
+
+func checkPackage(t *testing.T, importPath, source string) *gotypes.Package {
+	t.Helper()
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "test.go", source, 0)
+	if err != nil {
+		t.Fatalf("error parsing test source: %v", err)
+	}
+
+	//nolint:exhaustruct // defaults are fine
+	config := gotypes.Config{}
+
+	pkg, err := config.Check(importPath, fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatalf("error type checking test source: %v", err)
+	}
+
+	return pkg
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		label      string
+		oldSource  string
+		newSource  string
+		message    string
+		compatible bool
+	}{
+		{"function added", "package foo", "package foo\nfunc New() {}", "New: added", true},
+		{"function removed", "package foo\nfunc New() {}", "package foo", "New: removed", false},
+		{"signature changed", "package foo\nfunc New() {}", "package foo\nfunc New(string) {}", "New: changed from func() to func(string)", false},
+		{"field added", "package foo\ntype T struct{}", "package foo\ntype T struct{ A int }", "T.A: added", true},
+		{"field removed", "package foo\ntype T struct{ A int }", "package foo\ntype T struct{}", "T.A: removed", false},
+		{"method added", "package foo\ntype T int", "package foo\ntype T int\nfunc (T) M() {}", "T.M: added", true},
+		{"interface method added", "package foo\ntype I interface{}", "package foo\ntype I interface{ M() }", "I.M: added to interface", false},
+		{
+			"sealed interface method added",
+			"package foo\ntype I interface{ m() }",
+			"package foo\ntype I interface{ m(); M() }",
+			"I.M: added to interface",
+			true,
+		},
+		{"constant value changed", "package foo\nconst C = 1", "package foo\nconst C = 2", "C: value changed from 1 to 2", false},
+		{"kind changed", "package foo\nvar V = 1", "package foo\nconst V = 1", "V: changed from var to const", false},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			oldPkg := checkPackage(t, "go.anx.io/foo", testCase.oldSource)
+			newPkg := checkPackage(t, "go.anx.io/foo", testCase.newSource)
+
+			report := apidiff.Compare([]*gotypes.Package{oldPkg}, []*gotypes.Package{newPkg})
+
+			if len(report.Changes) != 1 {
+				t.Fatalf("expected exactly one change, got %v", report.Changes)
+			}
+
+			if report.Changes[0].Message != testCase.message {
+				t.Errorf("%q (actual) did not match %q (expected)", report.Changes[0].Message, testCase.message)
+			}
+
+			if report.Changes[0].Compatible != testCase.compatible {
+				t.Errorf("expected compatible to be %v", testCase.compatible)
+			}
+
+			if report.Incompatible() == testCase.compatible {
+				t.Errorf("expected report to be incompatible: %v", !testCase.compatible)
+			}
+		})
+	}
+}
+
+func TestCompareIgnoresInternalPackages(t *testing.T) {
+	t.Parallel()
+
+	oldPkg := checkPackage(t, "go.anx.io/foo/internal/bar", "package bar\nfunc New() {}")
+
+	report := apidiff.Compare([]*gotypes.Package{oldPkg}, nil)
+
+	if len(report.Changes) != 0 {
+		t.Errorf("expected no changes, got %v", report.Changes)
+	}
+}
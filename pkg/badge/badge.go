@@ -0,0 +1,82 @@
+// Package badge renders SVG badges in the style of shields.io, showing a label and a value.
+package badge
+
+import (
+	"fmt"
+	htmlEscape "html"
+	"io"
+	"math"
+)
+
+const (
+	// fontSize is the size of the badge text, widths below are for Verdana at this size.
+	fontSize = 11
+
+	// padding is the horizontal space left and right of each text.
+	padding = 6
+
+	// defaultCharWidth is used for characters missing from charWidths, wide enough for most of them.
+	defaultCharWidth = 7.0
+)
+
+// charWidths has the advance width in pixels of the printable ASCII characters in 11px Verdana,
+// the font badges are rendered in.
+//
+//nolint:gochecknoglobals // lookup table
+var charWidths = map[rune]float64{
+	' ': 3.87, '!': 4.33, '"': 5.05, '#': 9.0, '$': 7.0, '%': 11.84, '&': 7.99, '\'': 2.95,
+	'(': 4.99, ')': 4.99, '*': 7.0, '+': 9.0, ',': 4.0, '-': 4.99, '.': 4.0, '/': 4.99,
+	'0': 7.0, '1': 7.0, '2': 7.0, '3': 7.0, '4': 7.0, '5': 7.0, '6': 7.0, '7': 7.0, '8': 7.0, '9': 7.0,
+	':': 4.99, ';': 4.99, '<': 9.0, '=': 9.0, '>': 9.0, '?': 6.0, '@': 11.0,
+	'A': 7.52, 'B': 7.54, 'C': 7.68, 'D': 8.48, 'E': 6.96, 'F': 6.32, 'G': 8.53, 'H': 8.27, 'I': 4.61,
+	'J': 5.0, 'K': 7.62, 'L': 6.12, 'M': 9.27, 'N': 8.23, 'O': 8.66, 'P': 6.63, 'Q': 8.66, 'R': 7.65,
+	'S': 7.52, 'T': 6.78, 'U': 8.05, 'V': 7.52, 'W': 10.88, 'X': 7.54, 'Y': 6.77, 'Z': 7.54,
+	'[': 4.99, '\\': 4.99, ']': 4.99, '^': 9.0, '_': 7.0, '`': 7.0,
+	'a': 6.61, 'b': 6.85, 'c': 5.73, 'd': 6.85, 'e': 6.55, 'f': 3.87, 'g': 6.85, 'h': 6.96, 'i': 3.02,
+	'j': 3.79, 'k': 6.51, 'l': 3.02, 'm': 10.7, 'n': 6.96, 'o': 6.68, 'p': 6.85, 'q': 6.85, 'r': 4.69,
+	's': 5.73, 't': 4.33, 'u': 6.96, 'v': 6.51, 'w': 8.98, 'x': 6.51, 'y': 6.51, 'z': 5.78,
+	'{': 6.98, '|': 4.99, '}': 6.98, '~': 9.0,
+}
+
+// TextWidth returns the width in pixels the text takes up in a badge, rounded up.
+func TextWidth(text string) int {
+	width := 0.0
+
+	for _, char := range text {
+		if w, ok := charWidths[char]; ok {
+			width += w
+		} else {
+			width += defaultCharWidth
+		}
+	}
+
+	return int(math.Ceil(width))
+}
+
+// Render writes a badge with the given label on gray background and the value on the given color.
+func Render(writer io.Writer, label, value, color string) error {
+	labelWidth := TextWidth(label) + 2*padding
+	valueWidth := TextWidth(value) + 2*padding
+	width := labelWidth + valueWidth
+
+	label = htmlEscape.EscapeString(label)
+	value = htmlEscape.EscapeString(value)
+
+	// text is rendered at 10x scale to get sub-pixel positioning with integer coordinates, like shields.io does
+	_, err := fmt.Fprintf(writer, `<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]v: %[5]v">
+<title>%[4]v: %[5]v</title>
+<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[3]d" height="20" fill="%[6]v"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" text-rendering="geometricPrecision" font-size="%[7]d0" transform="scale(.1)">
+<text x="%[8]d" y="150" fill="#010101" fill-opacity=".3">%[4]v</text><text x="%[8]d" y="140">%[4]v</text>
+<text x="%[9]d" y="150" fill="#010101" fill-opacity=".3">%[5]v</text><text x="%[9]d" y="140">%[5]v</text>
+</g>
+</svg>
+`, width, labelWidth, valueWidth, label, value, color, fontSize, labelWidth*5, labelWidth*10+valueWidth*5)
+	if err != nil {
+		return fmt.Errorf("error writing badge: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package badge_test
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/anexia-it/go.anx.io/pkg/badge"
+)
+
+func TestTextWidth(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		label string
+		text  string
+		width int
+	}{
+		{"empty", "", 0},
+		{"digits", "v1.0.0", 36},
+		{"narrow and wide characters", "il", 7},
+		{"unknown characters use default width", "ü", 7},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			if width := badge.TextWidth(testCase.text); width != testCase.width {
+				t.Errorf("%v (actual) did not match %v (expected)", width, testCase.width)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	buffer := bytes.Buffer{}
+	if err := badge.Render(&buffer, "go.anx.io/<e5e>", "v1.3.0", "#007ec6"); err != nil {
+		t.Fatalf("error rendering badge: %v", err)
+	}
+
+	svg := buffer.String()
+
+	if !strings.Contains(svg, "go.anx.io/&lt;e5e&gt;: v1.3.0") {
+		t.Errorf("expected escaped label and value in badge, got %q", svg)
+	}
+
+	width := badge.TextWidth("go.anx.io/<e5e>") + badge.TextWidth("v1.3.0") + 24
+	if !strings.Contains(svg, `width="`+strconv.Itoa(width)+`"`) {
+		t.Errorf("expected badge to be %v pixels wide, got %q", width, svg)
+	}
+}
@@ -0,0 +1,118 @@
+// Package changelog parses CHANGELOG.md files following the format of https://keepachangelog.com.
+package changelog
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Section is the part of a changelog describing a single release.
+type Section struct {
+	// Title is the text of the sections heading, e.g. `[1.0.0] - 2024-01-10`.
+	Title string
+
+	// Version is the version the section is about, nil for sections not about a released version.
+	Version *semver.Version
+
+	// Date is the release date as written in the changelog, not parsed since formats vary.
+	Date string
+
+	// Content is the markdown of the section, without its heading.
+	Content string
+}
+
+// Changelog is a parsed changelog file.
+type Changelog struct {
+	Sections []Section
+}
+
+var (
+	sectionHeadingRegex = regexp.MustCompile(`^##\s+(.*?)\s*#*\s*$`)
+	sectionTitleRegex   = regexp.MustCompile(`^\[?([^\]\s]+)\]?(?:\s*[-–(]\s*([^)]+?)\)?)?$`)
+	fenceRegex          = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+)
+
+// Parse splits a changelog into its sections, each started by a level 2 heading.
+func Parse(contents string) Changelog {
+	ret := Changelog{Sections: make([]Section, 0)}
+
+	var (
+		current *Section
+		content strings.Builder
+		fence   string
+	)
+
+	finishSection := func() {
+		if current != nil {
+			current.Content = strings.TrimSpace(content.String())
+			ret.Sections = append(ret.Sections, *current)
+		}
+
+		content.Reset()
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(contents, "\r\n", "\n"), "\n") {
+		if match := fenceRegex.FindStringSubmatch(line); match != nil {
+			if fence == "" {
+				fence = match[1]
+			} else if fence == match[1] {
+				fence = ""
+			}
+		}
+
+		if fence == "" {
+			if match := sectionHeadingRegex.FindStringSubmatch(line); match != nil {
+				finishSection()
+
+				current = parseSectionTitle(match[1])
+
+				continue
+			}
+		}
+
+		if current != nil {
+			content.WriteString(line)
+			content.WriteString("\n")
+		}
+	}
+
+	finishSection()
+
+	return ret
+}
+
+func parseSectionTitle(title string) *Section {
+	section := Section{
+		Title:   title,
+		Version: nil,
+		Date:    "",
+		Content: "",
+	}
+
+	if match := sectionTitleRegex.FindStringSubmatch(title); match != nil {
+		if version, err := semver.NewVersion(match[1]); err == nil {
+			section.Version = version
+			section.Date = strings.TrimSpace(match[2])
+		}
+	}
+
+	return &section
+}
+
+// Find returns the section for the given version, nil if the changelog has no section for it.
+func (c Changelog) Find(version string) *Section {
+	wanted, err := semver.NewVersion(version)
+	if err != nil {
+		return nil
+	}
+
+	for i := range c.Sections {
+		if c.Sections[i].Version != nil && c.Sections[i].Version.Equal(wanted) {
+			return &c.Sections[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package changelog_test
+
+import (
+	"testing"
+
+	"github.com/anexia-it/go.anx.io/pkg/changelog"
+)
+
+const testChangelog = `# Changelog
+All notable changes to this project will be documented in this file.
+
+## [Unreleased]
+
+## [1.1.0] - 2024-02-10
+
+### Added
+- Client.Close
+
+` + "```" + `
+## not a heading
+` + "```" + `
+
+## v1.0.0 (2024-01-10)
+
+### Added
+- Initial release
+
+## [x.y.z] - YYYY-MM-DD
+`
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	parsed := changelog.Parse(testChangelog)
+
+	testCases := []struct {
+		label   string
+		version string
+		date    string
+		content string
+	}{
+		{"bracketed version", "v1.1.0", "2024-02-10", "### Added\n- Client.Close\n\n```\n## not a heading\n```"},
+		{"plain version with date in parens", "1.0.0", "2024-01-10", "### Added\n- Initial release"},
+		{"unknown version", "v2.0.0", "", ""},
+		{"branch", "main", "", ""},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			section := parsed.Find(testCase.version)
+
+			if testCase.content == "" {
+				if section != nil {
+					t.Errorf("expected no section, got %q", section.Title)
+				}
+
+				return
+			}
+
+			if section == nil {
+				t.Fatalf("expected section for %q, got none", testCase.version)
+			}
+
+			if section.Date != testCase.date {
+				t.Errorf("%q (actual) did not match %q (expected)", section.Date, testCase.date)
+			}
+
+			if section.Content != testCase.content {
+				t.Errorf("%q (actual) did not match %q (expected)", section.Content, testCase.content)
+			}
+		})
+	}
+
+	if len(parsed.Sections) != 4 {
+		t.Errorf("expected 4 sections, got %v", len(parsed.Sections))
+	}
+}
@@ -0,0 +1,177 @@
+// Package compression writes precompressed siblings of generated files and compresses HTTP responses.
+//
+// Only gzip is supported, as the standard library has no zstd implementation.
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Extension is appended to the name of a file for its precompressed sibling.
+const Extension = ".gz"
+
+// minSize is the size below which compressing is not worth it.
+const minSize = 256
+
+// Compressible returns true for files of text formats, which are worth compressing.
+func Compressible(name string) bool {
+	switch path.Ext(name) {
+	case ".html", ".css", ".js", ".json", ".xml", ".atom", ".svg", ".txt", ".md":
+		return true
+	default:
+		return false
+	}
+}
+
+// Gzip compresses the data with the best compression, since we compress once and serve many times.
+func Gzip(data []byte) ([]byte, error) {
+	buffer := bytes.Buffer{}
+
+	writer, err := gzip.NewWriterLevel(&buffer, gzip.BestCompression)
+	if err != nil {
+		return nil, fmt.Errorf("error creating gzip writer: %w", err)
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("error compressing data: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("error compressing data: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// WritePrecompressed writes the gzip compressed data next to the file, if the file is worth compressing.
+func WritePrecompressed(filePath string, data []byte) error {
+	if !Compressible(filePath) || len(data) < minSize {
+		return nil
+	}
+
+	compressed, err := Gzip(data)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filePath+Extension, compressed, 0644); err != nil {
+		return fmt.Errorf("error writing precompressed file %q: %w", filePath+Extension, err)
+	}
+
+	return nil
+}
+
+// AcceptsGzip returns true if the request accepts gzip encoded responses, respecting q=0 to refuse it.
+func AcceptsGzip(req *http.Request) bool {
+	for _, value := range req.Header.Values("Accept-Encoding") {
+		for _, encoding := range strings.Split(value, ",") {
+			name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "gzip" && name != "*" {
+				continue
+			}
+
+			quality := 1.0
+
+			if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+				if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+					quality = parsed
+				}
+			}
+
+			return quality > 0
+		}
+	}
+
+	return false
+}
+
+// compressibleContentType returns true for text content types, which are worth compressing.
+func compressibleContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return strings.HasPrefix(mediaType, "text/") ||
+		mediaType == "application/json" ||
+		mediaType == "application/xml" ||
+		mediaType == "application/atom+xml" ||
+		mediaType == "image/svg+xml"
+}
+
+type gzipResponseWriter struct {
+	http.ResponseWriter
+
+	writer      *gzip.Writer
+	wroteHeader bool
+}
+
+func (w *gzipResponseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+
+	w.wroteHeader = true
+	header := w.Header()
+
+	if header.Get("Content-Encoding") == "" && compressibleContentType(header.Get("Content-Type")) &&
+		statusCode != http.StatusNoContent && statusCode != http.StatusNotModified && statusCode != http.StatusPartialContent {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.writer = gzip.NewWriter(w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(data))
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.writer != nil {
+		return w.writer.Write(data) //nolint:wrapcheck // implementing io.Writer
+	}
+
+	return w.ResponseWriter.Write(data) //nolint:wrapcheck // implementing io.Writer
+}
+
+func (w *gzipResponseWriter) close() error {
+	if w.writer == nil {
+		return nil
+	}
+
+	return w.writer.Close() //nolint:wrapcheck // error of the response writer
+}
+
+// Handler compresses the responses of the given handler with gzip, for requests accepting it and responses with
+// text content types not already encoded. Range requests are not compressed, since the range refers to the
+// uncompressed contents.
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Vary", "Accept-Encoding")
+
+		if !AcceptsGzip(req) || req.Header.Get("Range") != "" {
+			next.ServeHTTP(res, req)
+			return
+		}
+
+		writer := &gzipResponseWriter{ResponseWriter: res, writer: nil, wroteHeader: false}
+		next.ServeHTTP(writer, req)
+
+		_ = writer.close()
+	})
+}
@@ -0,0 +1,102 @@
+package compression_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anexia-it/go.anx.io/pkg/compression"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		acceptEncoding string
+		accepts        bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"deflate, gzip;q=0.5, br", true},
+		{"gzip;q=0", false},
+		{"*", true},
+		{"br, zstd", false},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.acceptEncoding, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Encoding", testCase.acceptEncoding)
+
+			if accepts := compression.AcceptsGzip(req); accepts != testCase.accepts {
+				t.Errorf("%v (actual) did not match %v (expected)", accepts, testCase.accepts)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat("<p>hello world</p>", 100)
+
+	handler := compression.Handler(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/image.png" {
+			res.Header().Set("Content-Type", "image/png")
+		} else {
+			res.Header().Set("Content-Type", "text/html; charset=utf-8")
+		}
+
+		http.ServeContent(res, req, req.URL.Path, time.Time{}, strings.NewReader(body))
+	}))
+
+	testCases := []struct {
+		path         string
+		accept       string
+		rangeHeader  string
+		compressed   bool
+		expectedBody string
+	}{
+		{"/index.html", "gzip", "", true, body},
+		{"/index.html", "", "", false, body},
+		{"/image.png", "gzip", "", false, body},
+		{"/index.html", "gzip", "bytes=0-9", false, body[:10]},
+	}
+
+	for _, testCase := range testCases {
+		req := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+		req.Header.Set("Accept-Encoding", testCase.accept)
+
+		if testCase.rangeHeader != "" {
+			req.Header.Set("Range", testCase.rangeHeader)
+		}
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		reader := io.Reader(recorder.Body)
+
+		if encoding := recorder.Header().Get("Content-Encoding"); (encoding == "gzip") != testCase.compressed {
+			t.Errorf("unexpected Content-Encoding %q for %v with Accept-Encoding %q", encoding, testCase.path, testCase.accept)
+			continue
+		} else if encoding == "gzip" {
+			gzipReader, err := gzip.NewReader(recorder.Body)
+			if err != nil {
+				t.Fatalf("error reading compressed response: %v", err)
+			}
+
+			reader = gzipReader
+		}
+
+		if actual, err := io.ReadAll(reader); err != nil || string(actual) != testCase.expectedBody {
+			t.Errorf("unexpected response body for %v (error %v)", testCase.path, err)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"gopkg.in/yaml.v2"
 
+	"github.com/anexia-it/go.anx.io/pkg/forge"
 	"github.com/anexia-it/go.anx.io/pkg/types"
 )
 
@@ -30,6 +31,10 @@ func Load(filePath string) ([]*types.Package, error) {
 
 			pkg.TargetName = strings.TrimSuffix(path.Base(source.Path), ".git")
 		}
+
+		if _, err := forge.Templates(pkg); err != nil {
+			return nil, fmt.Errorf("error in config of package %q: %w", pkg.TargetName, err)
+		}
 	}
 
 	return ret, nil
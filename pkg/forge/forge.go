@@ -0,0 +1,127 @@
+// Package forge knows the URL schemes of source code hosting platforms, to link to directories, files and
+// lines in the source repository of a package.
+package forge
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+// ErrUnknownHost is returned for source hosts we have no preset for.
+var ErrUnknownHost = errors.New("unknown source host")
+
+// Presets are the URL templates of the supported source hosts.
+//
+//nolint:gochecknoglobals // lookup table
+var Presets = map[string]types.SourceURLTemplates{
+	"github": {
+		Directory: "{repo}/tree/{ref}{/dir}",
+		File:      "{repo}/blob/{ref}{/dir}/{file}",
+		Line:      "{repo}/blob/{ref}{/dir}/{file}#L{line}",
+	},
+	"gitlab": {
+		Directory: "{repo}/-/tree/{ref}{/dir}",
+		File:      "{repo}/-/blob/{ref}{/dir}/{file}",
+		Line:      "{repo}/-/blob/{ref}{/dir}/{file}#L{line}",
+	},
+	// Gitea and Forgejo need to know if a ref is a branch or a tag, using the commit avoids that.
+	"gitea": {
+		Directory: "{repo}/src/commit/{commit}{/dir}",
+		File:      "{repo}/src/commit/{commit}{/dir}/{file}",
+		Line:      "{repo}/src/commit/{commit}{/dir}/{file}#L{line}",
+	},
+	"bitbucket": {
+		Directory: "{repo}/src/{ref}{/dir}",
+		File:      "{repo}/src/{ref}{/dir}/{file}",
+		Line:      "{repo}/src/{ref}{/dir}/{file}#lines-{line}",
+	},
+}
+
+// Detect returns the name of the preset for the host of the given source URL, defaulting to "github".
+func Detect(source string) string {
+	host := ""
+	if u, err := url.Parse(source); err == nil {
+		host = strings.ToLower(u.Hostname())
+	}
+
+	switch {
+	case host == "bitbucket.org" || strings.Contains(host, "bitbucket"):
+		return "bitbucket"
+	case host == "gitlab.com" || strings.Contains(host, "gitlab"):
+		return "gitlab"
+	case host == "codeberg.org" || strings.Contains(host, "gitea") || strings.Contains(host, "forgejo"):
+		return "gitea"
+	default:
+		return "github"
+	}
+}
+
+// Templates returns the URL templates for the package, from the preset of its SourceHost (or the one
+// detected from its Source) with the templates configured for the package taking precedence.
+func Templates(pkg *types.Package) (types.SourceURLTemplates, error) {
+	host := pkg.SourceHost
+	if host == "" {
+		host = Detect(pkg.Source)
+	} else if host == "forgejo" {
+		host = "gitea"
+	}
+
+	ret, ok := Presets[host]
+	if !ok {
+		return ret, fmt.Errorf("%w: %q", ErrUnknownHost, pkg.SourceHost)
+	}
+
+	if pkg.SourceURLs.Directory != "" {
+		ret.Directory = pkg.SourceURLs.Directory
+	}
+
+	if pkg.SourceURLs.File != "" {
+		ret.File = pkg.SourceURLs.File
+	}
+
+	if pkg.SourceURLs.Line != "" {
+		ret.Line = pkg.SourceURLs.Line
+	}
+
+	return ret, nil
+}
+
+// Repository returns the URL of the web interface of the repository, which is the source without .git suffix.
+func Repository(source string) string {
+	return strings.TrimSuffix(source, ".git")
+}
+
+// Expand replaces the placeholders for the repository, version and commit, leaving the ones for directory,
+// file and line as they are, as needed for the go-source meta tag.
+func Expand(template, source, ref, commit string) string {
+	return strings.NewReplacer(
+		"{repo}", Repository(source),
+		"{ref}", ref,
+		"{commit}", commit,
+	).Replace(template)
+}
+
+// ExpandFile replaces all placeholders of an expanded template for the given file path, relative to the
+// repository root, and line, which is omitted if 0.
+func ExpandFile(template, filePath string, line int) string {
+	dir, file := path.Split(filePath)
+	dir = strings.TrimSuffix(dir, "/")
+
+	slashDir := ""
+	if dir != "" {
+		slashDir = "/" + dir
+	}
+
+	return strings.NewReplacer(
+		"{/dir}", slashDir,
+		"{dir}", dir,
+		"{file}", file,
+		"{line}", strconv.Itoa(line),
+	).Replace(template)
+}
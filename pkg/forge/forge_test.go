@@ -0,0 +1,93 @@
+package forge_test
+
+import (
+	"testing"
+
+	"github.com/anexia-it/go.anx.io/pkg/forge"
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		source string
+		host   string
+	}{
+		{"https://github.com/anexia/go-e5e.git", "github"},
+		{"https://gitlab.com/anexia/go-e5e.git", "gitlab"},
+		{"https://gitlab.example.com/anexia/go-e5e.git", "gitlab"},
+		{"https://codeberg.org/anexia/go-e5e.git", "gitea"},
+		{"https://bitbucket.org/anexia/go-e5e.git", "bitbucket"},
+		{"/tmp/some/local/repository", "github"},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.source, func(t *testing.T) {
+			t.Parallel()
+
+			if host := forge.Detect(testCase.source); host != testCase.host {
+				t.Errorf("%q (actual) did not match %q (expected)", host, testCase.host)
+			}
+		})
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	t.Parallel()
+
+	//nolint:exhaustruct // only the source fields are relevant
+	pkg := &types.Package{
+		Source:     "https://git.example.com/anexia/go-e5e.git",
+		SourceHost: "forgejo",
+		SourceURLs: types.SourceURLTemplates{Directory: "", File: "", Line: "{repo}/lines/{file}/{line}"},
+	}
+
+	templates, err := forge.Templates(pkg)
+	if err != nil {
+		t.Fatalf("error retrieving templates: %v", err)
+	}
+
+	if templates.File != forge.Presets["gitea"].File {
+		t.Errorf("expected file template of gitea preset, got %q", templates.File)
+	}
+
+	if templates.Line != "{repo}/lines/{file}/{line}" {
+		t.Errorf("expected configured line template, got %q", templates.Line)
+	}
+
+	pkg.SourceHost = "sourceforge"
+	if _, err := forge.Templates(pkg); err == nil {
+		t.Errorf("expected error for unknown source host")
+	}
+}
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		label    string
+		host     string
+		filePath string
+		line     int
+		expected string
+	}{
+		{"github file in root", "github", "main.go", 12, "https://example.com/o/r/blob/v1.0.0/main.go#L12"},
+		{"gitlab file in subdirectory", "gitlab", "pkg/foo/foo.go", 3, "https://example.com/o/r/-/blob/v1.0.0/pkg/foo/foo.go#L3"},
+		{"gitea uses commit", "gitea", "main.go", 1, "https://example.com/o/r/src/commit/abc123/main.go#L1"},
+		{"bitbucket", "bitbucket", "a/b.go", 7, "https://example.com/o/r/src/v1.0.0/a/b.go#lines-7"},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			template := forge.Expand(forge.Presets[testCase.host].Line, "https://example.com/o/r.git", "v1.0.0", "abc123")
+			if actual := forge.ExpandFile(template, testCase.filePath, testCase.line); actual != testCase.expected {
+				t.Errorf("%q (actual) did not match %q (expected)", actual, testCase.expected)
+			}
+		})
+	}
+}
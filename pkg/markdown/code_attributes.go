@@ -0,0 +1,125 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// codeBlockAttributes are the attributes given in the info string of a fenced code block, after the
+// language in braces: ```go {hl_lines=[3,5-7] linenos=false title="main.go" diff=true}
+type codeBlockAttributes struct {
+	// highlightLines are the ranges of lines to highlight, both ends included, starting at 1.
+	highlightLines [][2]int
+
+	lineNumbers bool
+	title       string
+
+	// diff renders the code as diff, lines starting with + and - are marked as added and removed while
+	// the rest of the line is highlighted in the language of the block.
+	diff bool
+}
+
+var (
+	codeAttributeRegex = regexp.MustCompile(`([\w-]+)=("(?:[^"\\]|\\.)*"|\[[^\]]*\]|[^\s}]+)`)
+	lineRangeRegex     = regexp.MustCompile(`^(\d+)(?:-(\d+))?$`)
+)
+
+// parseCodeBlockInfo splits the info string of a fenced code block into language and attributes, unknown
+// attributes and invalid values are ignored.
+func parseCodeBlockInfo(info string) (string, codeBlockAttributes) {
+	attributes := codeBlockAttributes{
+		highlightLines: nil,
+		lineNumbers:    true,
+		title:          "",
+		diff:           false,
+	}
+
+	language, attributeString, _ := strings.Cut(info, "{")
+
+	// like goldmark, we take the first word as language
+	if fields := strings.Fields(language); len(fields) > 0 {
+		language = fields[0]
+	} else {
+		language = ""
+	}
+
+	for _, match := range codeAttributeRegex.FindAllStringSubmatch(attributeString, -1) {
+		key, value := match[1], match[2]
+
+		switch key {
+		case "hl_lines":
+			attributes.highlightLines = parseLineRanges(value)
+		case "linenos":
+			if enabled, err := strconv.ParseBool(strings.Trim(value, `"`)); err == nil {
+				attributes.lineNumbers = enabled
+			}
+		case "title":
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				attributes.title = unquoted
+			} else {
+				attributes.title = value
+			}
+		case "diff":
+			if enabled, err := strconv.ParseBool(strings.Trim(value, `"`)); err == nil {
+				attributes.diff = enabled
+			}
+		}
+	}
+
+	return language, attributes
+}
+
+// parseLineRanges parses lists of lines and line ranges like `[3,5-7]`.
+func parseLineRanges(value string) [][2]int {
+	ret := make([][2]int, 0)
+
+	for _, element := range strings.Split(strings.Trim(value, `[]"`), ",") {
+		match := lineRangeRegex.FindStringSubmatch(strings.TrimSpace(element))
+		if match == nil {
+			continue
+		}
+
+		start, _ := strconv.Atoi(match[1])
+		end := start
+
+		if match[2] != "" {
+			end, _ = strconv.Atoi(match[2])
+		}
+
+		ret = append(ret, [2]int{start, end})
+	}
+
+	return ret
+}
+
+// diffLineKind is the kind of a line in diff mode, "added", "removed" or "" for context lines.
+type diffLineKind string
+
+// splitDiff removes the diff markers from the start of every line, returning the code and the kinds of lines.
+func splitDiff(code string) (string, []diffLineKind) {
+	lines := strings.SplitAfter(code, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	kinds := make([]diffLineKind, len(lines))
+	stripped := strings.Builder{}
+
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			kinds[i] = "added"
+			line = line[1:]
+		case strings.HasPrefix(line, "-"):
+			kinds[i] = "removed"
+			line = line[1:]
+		case strings.HasPrefix(line, " "):
+			line = line[1:]
+		}
+
+		stripped.WriteString(line)
+	}
+
+	return stripped.String(), kinds
+}
@@ -1,8 +1,14 @@
 package markdown
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	htmlEscape "html"
 	"io"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/alecthomas/chroma"
@@ -16,7 +22,19 @@ import (
 	"github.com/yuin/goldmark/util"
 )
 
-var chromaStyle = styles.Get("pygments")
+const (
+	// DefaultCodeStyle is the chroma style used for highlighting code if not configured otherwise.
+	DefaultCodeStyle = "pygments"
+
+	// DefaultDarkCodeStyle is the chroma style used for browsers preferring a dark color scheme.
+	DefaultDarkCodeStyle = "monokai"
+)
+
+// ErrUnknownStyle is returned for code highlighting styles chroma does not know.
+var ErrUnknownStyle = errors.New("unknown code highlighting style")
+
+// chromaStyle is only given to the formatter, the actual style is applied with the stylesheet from RenderCodeCSS.
+var chromaStyle = styles.Get(DefaultCodeStyle)
 
 var chromaFormatterOpts = []html.Option{
 	html.Standalone(false),
@@ -34,11 +52,15 @@ var chromaFormatterOpts = []html.Option{
 //	-- Mara @LittleFox94 Grosch, 2021-12-15
 type codeHighlighterImpl struct {
 	codeIDCounter int
+	linker        CodeLinker
+	copyButton    string
 }
 
-func codeHighlighter() *codeHighlighterImpl {
+func codeHighlighter(options renderOptions) *codeHighlighterImpl {
 	return &codeHighlighterImpl{
 		codeIDCounter: 1,
+		linker:        options.codeLinker,
+		copyButton:    options.copyButton,
 	}
 }
 
@@ -64,8 +86,10 @@ func (ch *codeHighlighterImpl) renderHighlightedCode(destinationStream util.BufW
 	}
 
 	language := ""
-	if fcb, ok := node.(*ast.FencedCodeBlock); ok {
-		language = string(fcb.Language(source))
+	attributes := codeBlockAttributes{highlightLines: nil, lineNumbers: true, title: "", diff: false}
+
+	if fcb, ok := node.(*ast.FencedCodeBlock); ok && fcb.Info != nil {
+		language, attributes = parseCodeBlockInfo(string(fcb.Info.Segment.Value(source)))
 	}
 
 	code := strings.Builder{}
@@ -75,10 +99,22 @@ func (ch *codeHighlighterImpl) renderHighlightedCode(destinationStream util.BufW
 		code.Write(line.Value(source))
 	}
 
+	codeString := code.String()
+
+	var diffLines []diffLineKind
+	if attributes.diff {
+		codeString, diffLines = splitDiff(codeString)
+	}
+
+	var annotations []CodeAnnotation
+	if ch.linker != nil {
+		annotations = ch.linker(language, codeString)
+	}
+
 	var lexer chroma.Lexer
 
 	if language == "" {
-		lexer = lexers.Analyse(code.String())
+		lexer = lexers.Analyse(codeString)
 	} else {
 		lexer = lexers.Get(language)
 	}
@@ -87,32 +123,238 @@ func (ch *codeHighlighterImpl) renderHighlightedCode(destinationStream util.BufW
 		lexer = lexers.Fallback
 	}
 
-	tokens, err := lexer.Tokenise(nil, code.String())
+	tokens, err := chroma.Tokenise(lexer, nil, codeString)
 	if err != nil {
 		return ast.WalkContinue, err //nolint:wrapcheck
 	}
 
+	tokens, placedAnnotations := annotateTokens(tokens, annotations)
+
 	codeLinkID := fmt.Sprintf("code-%v-", ch.codeIDCounter)
 	ch.codeIDCounter++
 
 	formatter := html.New(
-		append(chromaFormatterOpts, html.LinkableLineNumbers(true, codeLinkID))...,
+		append(chromaFormatterOpts,
+			html.LinkableLineNumbers(true, codeLinkID),
+			html.WithLineNumbers(attributes.lineNumbers),
+			html.HighlightLines(attributes.highlightLines),
+		)...,
 	)
 
-	status, err := ast.WalkContinue, formatter.Format(destinationStream, chromaStyle, tokens)
+	highlighted := bytes.Buffer{}
+
+	status, err := ast.WalkContinue, formatter.Format(&highlighted, chromaStyle, chroma.Literator(tokens...))
 	if err != nil {
 		return status, fmt.Errorf("error walking AST: %w", err)
 	}
 
+	output := replaceAnnotationPlaceholders(highlighted.Bytes(), placedAnnotations)
+	output = markDiffLines(output, diffLines)
+
+	if _, err := destinationStream.Write(ch.wrapCodeBlock(output, attributes)); err != nil {
+		return status, fmt.Errorf("error writing highlighted code: %w", err)
+	}
+
 	return status, nil
 }
 
-func RenderCodeCSS(w io.Writer) error {
+var formattedLineRegex = regexp.MustCompile(`<span class="line( hl)?">`)
+
+// markDiffLines adds the diffAdded and diffRemoved classes to the lines of the formatted code.
+func markDiffLines(formatted []byte, kinds []diffLineKind) []byte {
+	if kinds == nil {
+		return formatted
+	}
+
+	line := 0
+
+	return formattedLineRegex.ReplaceAllFunc(formatted, func(match []byte) []byte {
+		kind := diffLineKind("")
+		if line < len(kinds) {
+			kind = kinds[line]
+		}
+
+		line++
+
+		switch kind {
+		case "added":
+			return bytes.Replace(match, []byte(`class="line`), []byte(`class="line diffAdded`), 1)
+		case "removed":
+			return bytes.Replace(match, []byte(`class="line`), []byte(`class="line diffRemoved`), 1)
+		default:
+			return match
+		}
+	})
+}
+
+// wrapCodeBlock adds the title and copy button to the formatted code, if any of them is wanted.
+func (ch *codeHighlighterImpl) wrapCodeBlock(formatted []byte, attributes codeBlockAttributes) []byte {
+	if attributes.title == "" && ch.copyButton == "" {
+		return formatted
+	}
+
+	ret := bytes.Buffer{}
+	ret.WriteString(`<div class="codeBlock"><div class="codeHeader">`)
+
+	if attributes.title != "" {
+		ret.WriteString(`<span class="codeTitle">` + htmlEscape.EscapeString(attributes.title) + `</span>`)
+	}
+
+	if ch.copyButton != "" {
+		ret.WriteString(`<button type="button" class="copyCode">` + htmlEscape.EscapeString(ch.copyButton) + `</button>`)
+	}
+
+	ret.WriteString(`</div>`)
+	ret.Write(formatted)
+	ret.WriteString(`</div>`)
+
+	return ret.Bytes()
+}
+
+// Chroma does not allow adding links to the formatted code, so we replace every annotated piece of
+// code with a placeholder (made from characters in the unicode private use area, which are kept
+// as-is by the HTML formatter) and replace those with the links after formatting.
+var annotationPlaceholderRegex = regexp.MustCompile("\uE000(\\d+)\uE001")
+
+type placedAnnotation struct {
+	CodeAnnotation
+	code string
+}
+
+// annotateTokens splits the tokens at the annotated spans of code, replacing them with placeholders.
+// It returns the annotations in the order of the placeholder indices, annotations not matching token
+// boundaries are dropped.
+func annotateTokens(tokens []chroma.Token, annotations []CodeAnnotation) ([]chroma.Token, []placedAnnotation) {
+	if len(annotations) == 0 {
+		return tokens, nil
+	}
+
+	sort.SliceStable(annotations, func(a, b int) bool {
+		return annotations[a].Offset < annotations[b].Offset
+	})
+
+	ret := make([]chroma.Token, 0, len(tokens)+2*len(annotations))
+	placed := make([]placedAnnotation, 0, len(annotations))
+
+	offset := 0
+	next := 0
+
+	for _, token := range tokens {
+		tokenEnd := offset + len(token.Value)
+
+		for next < len(annotations) && annotations[next].Offset < tokenEnd {
+			annotation := annotations[next]
+			next++
+
+			annotationEnd := annotation.Offset + annotation.Length
+			if annotation.Offset < offset || annotationEnd > tokenEnd || annotation.Length <= 0 {
+				continue
+			}
+
+			if prefix := token.Value[:annotation.Offset-offset]; prefix != "" {
+				ret = append(ret, chroma.Token{Type: token.Type, Value: prefix})
+			}
+
+			ret = append(ret, chroma.Token{
+				Type:  token.Type,
+				Value: fmt.Sprintf("\uE000%d\uE001", len(placed)),
+			})
+
+			placed = append(placed, placedAnnotation{
+				CodeAnnotation: annotation,
+				code:           token.Value[annotation.Offset-offset : annotationEnd-offset],
+			})
+
+			token.Value = token.Value[annotationEnd-offset:]
+			offset = annotationEnd
+		}
+
+		if token.Value != "" {
+			ret = append(ret, token)
+		}
+
+		offset = tokenEnd
+	}
+
+	return ret, placed
+}
+
+func replaceAnnotationPlaceholders(highlighted []byte, annotations []placedAnnotation) []byte {
+	if len(annotations) == 0 {
+		return highlighted
+	}
+
+	return annotationPlaceholderRegex.ReplaceAllFunc(highlighted, func(placeholder []byte) []byte {
+		index, err := strconv.Atoi(string(annotationPlaceholderRegex.FindSubmatch(placeholder)[1]))
+		if err != nil || index >= len(annotations) {
+			return placeholder
+		}
+
+		annotation := annotations[index]
+
+		link := strings.Builder{}
+		link.WriteString("<a")
+
+		if annotation.ID != "" {
+			link.WriteString(` id="` + htmlEscape.EscapeString(annotation.ID) + `"`)
+		}
+
+		if annotation.Href != "" {
+			link.WriteString(` href="` + htmlEscape.EscapeString(annotation.Href) + `"`)
+		}
+
+		link.WriteString(">" + htmlEscape.EscapeString(annotation.code) + "</a>")
+
+		return []byte(link.String())
+	})
+}
+
+// RenderCodeCSS writes the stylesheet for highlighted code with the given chroma style. Unless darkStyle is empty,
+// the style is scoped to browsers not preferring a dark color scheme and followed by the dark style scoped to the
+// ones preferring it, so no colors of one style leak into the other.
+func RenderCodeCSS(w io.Writer, style, darkStyle string) error {
 	formatter := html.New(chromaFormatterOpts...)
 
-	if err := formatter.WriteCSS(w, chromaStyle); err != nil {
+	lightChromaStyle, err := lookupStyle(style)
+	if err != nil {
+		return err
+	}
+
+	if darkStyle == "" {
+		if err := formatter.WriteCSS(w, lightChromaStyle); err != nil {
+			return fmt.Errorf("error writing CSS for code highlighting style: %w", err)
+		}
+
+		return nil
+	}
+
+	darkChromaStyle, err := lookupStyle(darkStyle)
+	if err != nil {
+		return err
+	}
+
+	lightCSS := bytes.Buffer{}
+	if err := formatter.WriteCSS(&lightCSS, lightChromaStyle); err != nil {
 		return fmt.Errorf("error writing CSS for code highlighting style: %w", err)
 	}
 
+	darkCSS := bytes.Buffer{}
+	if err := formatter.WriteCSS(&darkCSS, darkChromaStyle); err != nil {
+		return fmt.Errorf("error writing CSS for dark code highlighting style: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "@media not (prefers-color-scheme: dark) {\n%v}\n@media (prefers-color-scheme: dark) {\n%v}\n", lightCSS.String(), darkCSS.String()); err != nil {
+		return fmt.Errorf("error writing CSS for code highlighting styles: %w", err)
+	}
+
 	return nil
 }
+
+func lookupStyle(name string) (*chroma.Style, error) {
+	style, ok := styles.Registry[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStyle, name)
+	}
+
+	return style, nil
+}
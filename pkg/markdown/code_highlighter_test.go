@@ -0,0 +1,130 @@
+package markdown_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anexia-it/go.anx.io/pkg/markdown"
+)
+
+func TestCodeBlockAttributes(t *testing.T) {
+	t.Parallel()
+
+	const code = "package main\n\nfunc main() {\n}\n"
+
+	testCases := []struct {
+		label       string
+		info        string
+		code        string
+		options     []markdown.Option
+		contains    []string
+		notContains []string
+	}{
+		{"plain", "go", code, nil, []string{`<span class="kn">package</span>`, `class="ln"`}, []string{"codeBlock", `class="line hl"`}},
+		{"highlighted lines", "go {hl_lines=[1,3-4]}", code, nil, []string{`<span class="line hl">`}, nil},
+		{"without line numbers", "go {linenos=false}", code, nil, []string{`<span class="kn">package</span>`}, []string{`class="ln"`}},
+		{
+			"title",
+			`go {title="main.go"}`,
+			code,
+			nil,
+			[]string{`<div class="codeBlock">`, `<span class="codeTitle">main.go</span>`},
+			[]string{"copyCode"},
+		},
+		{
+			"copy button",
+			"go",
+			code,
+			[]markdown.Option{markdown.WithCopyButton("Copy")},
+			[]string{`<button type="button" class="copyCode">Copy</button>`},
+			[]string{"codeTitle"},
+		},
+		{
+			"diff",
+			"go {diff=true}",
+			" package main\n-func old() {}\n+func main() {}\n",
+			nil,
+			[]string{`<span class="line diffRemoved">`, `<span class="line diffAdded">`, `<span class="kd">func</span>`},
+			[]string{"+func", "-func"},
+		},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			rendered, err := markdown.RenderMarkdown("```"+testCase.info+"\n"+testCase.code+"```\n", testCase.options...)
+			if err != nil {
+				t.Fatalf("error rendering markdown: %v", err)
+			}
+
+			for _, expected := range testCase.contains {
+				if !strings.Contains(string(rendered), expected) {
+					t.Errorf("expected %q in rendered code block %q", expected, rendered)
+				}
+			}
+
+			for _, unexpected := range testCase.notContains {
+				if strings.Contains(string(rendered), unexpected) {
+					t.Errorf("did not expect %q in rendered code block %q", unexpected, rendered)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderCodeCSS(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		label     string
+		darkStyle string
+		prefixes  []string
+	}{
+		{"light only", "", nil},
+		{"light and dark", markdown.DefaultDarkCodeStyle, []string{"@media not (prefers-color-scheme: dark) {", "@media (prefers-color-scheme: dark) {"}},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			css := strings.Builder{}
+			if err := markdown.RenderCodeCSS(&css, markdown.DefaultCodeStyle, testCase.darkStyle); err != nil {
+				t.Fatalf("error rendering CSS: %v", err)
+			}
+
+			// every rule has to be inside one of the expected blocks, so one style cannot leak into the other
+			depth := 0
+			blocks := make([]string, 0)
+
+			for _, line := range strings.Split(css.String(), "\n") {
+				if depth == 0 && strings.TrimSpace(line) != "" {
+					blocks = append(blocks, line)
+				}
+
+				depth += strings.Count(line, "{") - strings.Count(line, "}")
+			}
+
+			if testCase.darkStyle == "" {
+				if strings.Contains(css.String(), "@media") {
+					t.Errorf("expected no media queries without dark style:\n%v", css.String())
+				}
+
+				return
+			}
+
+			if len(blocks) != len(testCase.prefixes) {
+				t.Fatalf("expected %v top-level blocks, got %v", len(testCase.prefixes), blocks)
+			}
+
+			for i, prefix := range testCase.prefixes {
+				if !strings.HasPrefix(blocks[i], prefix) {
+					t.Errorf("expected top-level block %v to start with %q, got %q", i, prefix, blocks[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package markdown
+
+// emojis maps the GitHub shortcodes of commonly used emojis to the emoji.
+var emojis = map[string]string{
+	"+1":                         "👍",
+	"-1":                         "👎",
+	"100":                        "💯",
+	"adhesive_bandage":           "🩹",
+	"airplane":                   "✈️",
+	"alarm_clock":                "⏰",
+	"alien":                      "👽",
+	"apple":                      "🍎",
+	"arrow_down":                 "⬇️",
+	"arrow_down_small":           "🔽",
+	"arrow_left":                 "⬅️",
+	"arrow_right":                "➡️",
+	"arrow_up":                   "⬆️",
+	"arrow_up_small":             "🔼",
+	"art":                        "🎨",
+	"ballot_box_with_check":      "☑️",
+	"bar_chart":                  "📊",
+	"battery":                    "🔋",
+	"beers":                      "🍻",
+	"bell":                       "🔔",
+	"blue_heart":                 "💙",
+	"book":                       "📖",
+	"bookmark":                   "🔖",
+	"books":                      "📚",
+	"boom":                       "💥",
+	"bug":                        "🐛",
+	"building_construction":      "🏗️",
+	"bulb":                       "💡",
+	"busts_in_silhouette":        "👥",
+	"calendar":                   "📆",
+	"card_file_box":              "🗃️",
+	"cd":                         "💿",
+	"chart_with_downwards_trend": "📉",
+	"chart_with_upwards_trend":   "📈",
+	"checkered_flag":             "🏁",
+	"children_crossing":          "🚸",
+	"clap":                       "👏",
+	"clipboard":                  "📋",
+	"closed_lock_with_key":       "🔐",
+	"cloud":                      "☁️",
+	"coffee":                     "☕",
+	"coffin":                     "⚰️",
+	"computer":                   "💻",
+	"construction":               "🚧",
+	"construction_worker":        "👷",
+	"copyright":                  "©️",
+	"cry":                        "😢",
+	"date":                       "📅",
+	"dizzy":                      "💫",
+	"electric_plug":              "🔌",
+	"email":                      "📧",
+	"envelope":                   "✉️",
+	"exclamation":                "❗",
+	"eyes":                       "👀",
+	"file_folder":                "📁",
+	"fire":                       "🔥",
+	"floppy_disk":                "💾",
+	"gear":                       "⚙️",
+	"ghost":                      "👻",
+	"gift":                       "🎁",
+	"globe_with_meridians":       "🌐",
+	"goal_net":                   "🥅",
+	"green_apple":                "🍏",
+	"green_heart":                "💚",
+	"grinning":                   "😀",
+	"hammer":                     "🔨",
+	"hammer_and_wrench":          "🛠️",
+	"hankey":                     "💩",
+	"heart":                      "❤️",
+	"heart_eyes":                 "😍",
+	"heavy_check_mark":           "✔️",
+	"heavy_exclamation_mark":     "❗",
+	"heavy_minus_sign":           "➖",
+	"heavy_multiplication_x":     "✖️",
+	"heavy_plus_sign":            "➕",
+	"hourglass":                  "⌛",
+	"hourglass_flowing_sand":     "⏳",
+	"hugs":                       "🤗",
+	"inbox_tray":                 "📥",
+	"information_source":         "ℹ️",
+	"joy":                        "😂",
+	"key":                        "🔑",
+	"label":                      "🏷️",
+	"lady_beetle":                "🐞",
+	"link":                       "🔗",
+	"lipstick":                   "💄",
+	"lock":                       "🔒",
+	"lock_with_ink_pen":          "🔏",
+	"loud_sound":                 "🔊",
+	"loudspeaker":                "📢",
+	"mag":                        "🔍",
+	"mega":                       "📣",
+	"memo":                       "📝",
+	"microscope":                 "🔬",
+	"money_with_wings":           "💸",
+	"monocle_face":               "🧐",
+	"muscle":                     "💪",
+	"mute":                       "🔇",
+	"new":                        "🆕",
+	"no_entry":                   "⛔",
+	"no_entry_sign":              "🚫",
+	"o":                          "⭕",
+	"ok_hand":                    "👌",
+	"open_file_folder":           "📂",
+	"outbox_tray":                "📤",
+	"package":                    "📦",
+	"page_facing_up":             "📄",
+	"passport_control":           "🛂",
+	"pencil":                     "📝",
+	"pencil2":                    "✏️",
+	"penguin":                    "🐧",
+	"point_right":                "👉",
+	"poop":                       "💩",
+	"pray":                       "🙏",
+	"pushpin":                    "📌",
+	"question":                   "❓",
+	"rage":                       "😡",
+	"raised_hands":               "🙌",
+	"recycle":                    "♻️",
+	"registered":                 "®️",
+	"rewind":                     "⏪",
+	"robot":                      "🤖",
+	"rocket":                     "🚀",
+	"rotating_light":             "🚨",
+	"safety_vest":                "🦺",
+	"scroll":                     "📜",
+	"see_no_evil":                "🙈",
+	"seedling":                   "🌱",
+	"shield":                     "🛡️",
+	"skull":                      "💀",
+	"smile":                      "😄",
+	"smiley":                     "😃",
+	"smirk":                      "😏",
+	"snowflake":                  "❄️",
+	"sparkles":                   "✨",
+	"speak_no_evil":              "🙊",
+	"speech_balloon":             "💬",
+	"star":                       "⭐",
+	"star2":                      "🌟",
+	"stop_sign":                  "🛑",
+	"stopwatch":                  "⏱️",
+	"sunglasses":                 "😎",
+	"sunny":                      "☀️",
+	"sweat_smile":                "😅",
+	"tada":                       "🎉",
+	"technologist":               "🧑‍💻",
+	"test_tube":                  "🧪",
+	"thinking":                   "🤔",
+	"thread":                     "🧵",
+	"thumbsdown":                 "👎",
+	"thumbsup":                   "👍",
+	"timer_clock":                "⏲️",
+	"tm":                         "™️",
+	"triangular_flag_on_post":    "🚩",
+	"trophy":                     "🏆",
+	"truck":                      "🚚",
+	"twisted_rightwards_arrows":  "🔀",
+	"umbrella":                   "☔",
+	"unlock":                     "🔓",
+	"warning":                    "⚠️",
+	"wastebasket":                "🗑️",
+	"wave":                       "👋",
+	"whale":                      "🐳",
+	"white_check_mark":           "✅",
+	"white_flag":                 "🏳️",
+	"wink":                       "😉",
+	"wrench":                     "🔧",
+	"x":                          "❌",
+	"zap":                        "⚡",
+}
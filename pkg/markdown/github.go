@@ -0,0 +1,283 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	htmlRenderer "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// githubExtensions implements the parts of GitHub flavored markdown not covered by goldmark's extensions:
+// alerts, emoji shortcodes, heading ids with anchor links and prefixed ids in raw HTML. Heading ids are generated by githubIDs, which
+// has to be given to the parser with newParserContext.
+type githubExtensions struct{}
+
+func (githubExtensions) Extend(md goldmark.Markdown) {
+	md.Parser().AddOptions(
+		parser.WithASTTransformers(
+			util.PrioritizedValue{Value: alertTransformer{}, Priority: 0},
+			util.PrioritizedValue{Value: headingIDTransformer{}, Priority: 0},
+		),
+		parser.WithInlineParsers(
+			util.PrioritizedValue{Value: emojiParser{}, Priority: 999},
+		),
+	)
+
+	md.Renderer().AddOptions(
+		renderer.WithNodeRenderers(
+			util.PrioritizedValue{Value: githubRenderer{}, Priority: 0},
+			util.PrioritizedValue{Value: rawHTMLRenderer{}, Priority: 0},
+		),
+	)
+}
+
+// newParserContext creates the context for parsing a document, generating heading ids like GitHub does.
+func newParserContext() parser.Context {
+	return parser.NewContext(parser.WithIDs(&githubIDs{used: map[string]int{}}))
+}
+
+// githubIDs generates heading ids the same way GitHub does, so links to sections of a README on GitHub
+// work for our pages, too.
+type githubIDs struct {
+	used map[string]int
+}
+
+func (ids *githubIDs) Generate(value []byte, _ ast.NodeKind) []byte {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r == ' ':
+			return '-'
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r):
+			return r
+		default:
+			return -1
+		}
+	}, strings.ToLower(string(value)))
+
+	if slug == "" {
+		slug = "heading"
+	}
+
+	result := slug
+	for _, exists := ids.used[result]; exists; _, exists = ids.used[result] {
+		ids.used[slug]++
+		result = slug + "-" + strconv.Itoa(ids.used[slug])
+	}
+
+	ids.used[result] = 0
+
+	return []byte(result)
+}
+
+func (ids *githubIDs) Put(value []byte) {
+	ids.used[string(value)] = 0
+}
+
+// headingIDTransformer sets the id of every heading. Other than goldmark's auto heading ids, which are
+// generated from the markdown source, we use the text of the heading like GitHub does, without markup,
+// link targets and emoji shortcodes.
+type headingIDTransformer struct{}
+
+func (headingIDTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := node.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+
+		if _, ok := heading.AttributeString("id"); !ok {
+			heading.SetAttributeString("id", pc.IDs().Generate([]byte(plainText(heading, source)), ast.KindHeading))
+		}
+
+		return ast.WalkSkipChildren, nil
+	})
+}
+
+// plainText returns the text of the given node as shown in the browser.
+func plainText(node ast.Node, source []byte) string {
+	ret := strings.Builder{}
+
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		switch n := child.(type) {
+		case *ast.Text:
+			ret.Write(n.Segment.Value(source))
+		case *ast.String:
+			// typographer replacements are HTML entities
+			ret.WriteString(html.UnescapeString(string(n.Value)))
+		case *ast.AutoLink:
+			ret.Write(n.Label(source))
+		case *ast.RawHTML:
+			continue
+		default:
+			ret.WriteString(plainText(n, source))
+		}
+	}
+
+	return ret.String()
+}
+
+var kindAlert = ast.NewNodeKind("Alert")
+
+// alertNode is a blockquote starting with a marker like `[!NOTE]`.
+type alertNode struct {
+	ast.BaseBlock
+
+	alertType string
+}
+
+func (n *alertNode) Kind() ast.NodeKind {
+	return kindAlert
+}
+
+func (n *alertNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Type": n.alertType}, nil)
+}
+
+var alertTypes = map[string]string{
+	"note":      "Note",
+	"tip":       "Tip",
+	"important": "Important",
+	"warning":   "Warning",
+	"caution":   "Caution",
+}
+
+var alertMarkerRegex = regexp.MustCompile(`^\[!([A-Za-z]+)\]\s*$`)
+
+// alertTransformer replaces top-level blockquotes starting with an alert marker on its own line with alertNodes.
+type alertTransformer struct{}
+
+func (alertTransformer) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+
+	for node := doc.FirstChild(); node != nil; node = node.NextSibling() {
+		quote, ok := node.(*ast.Blockquote)
+		if !ok {
+			continue
+		}
+
+		paragraph, ok := quote.FirstChild().(*ast.Paragraph)
+		if !ok || paragraph.Lines().Len() == 0 {
+			continue
+		}
+
+		markerLine := paragraph.Lines().At(0)
+
+		match := alertMarkerRegex.FindSubmatch(markerLine.Value(source))
+		if match == nil {
+			continue
+		}
+
+		alertType := strings.ToLower(string(match[1]))
+		if _, ok := alertTypes[alertType]; !ok {
+			continue
+		}
+
+		// the marker is made of text nodes, everything after it is on the following lines
+		for child := paragraph.FirstChild(); child != nil; child = paragraph.FirstChild() {
+			if t, ok := child.(*ast.Text); !ok || t.Segment.Start >= markerLine.Stop {
+				break
+			}
+
+			paragraph.RemoveChild(paragraph, child)
+		}
+
+		if !paragraph.HasChildren() {
+			quote.RemoveChild(quote, paragraph)
+		}
+
+		alert := &alertNode{BaseBlock: ast.BaseBlock{}, alertType: alertType}
+		for child := quote.FirstChild(); child != nil; child = quote.FirstChild() {
+			alert.AppendChild(alert, child)
+		}
+
+		doc.ReplaceChild(doc, quote, alert)
+		node = alert
+	}
+}
+
+var emojiShortcodeRegex = regexp.MustCompile(`^:([a-z0-9_+-]+):`)
+
+// emojiParser replaces shortcodes like `:tada:` with the emoji.
+type emojiParser struct{}
+
+func (emojiParser) Trigger() []byte {
+	return []byte{':'}
+}
+
+func (emojiParser) Parse(_ ast.Node, block text.Reader, _ parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+
+	match := emojiShortcodeRegex.FindSubmatch(line)
+	if match == nil {
+		return nil
+	}
+
+	emoji, ok := emojis[string(match[1])]
+	if !ok {
+		return nil
+	}
+
+	block.Advance(len(match[0]))
+
+	return ast.NewString([]byte(emoji))
+}
+
+// githubRenderer renders alerts and headings with anchor links like GitHub does.
+type githubRenderer struct{}
+
+func (r githubRenderer) RegisterFuncs(nrfr renderer.NodeRendererFuncRegisterer) {
+	nrfr.Register(kindAlert, r.renderAlert)
+	nrfr.Register(ast.KindHeading, r.renderHeading)
+}
+
+func (githubRenderer) renderAlert(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	//nolint:forcetypeassert // only registered for kindAlert
+	alert := node.(*alertNode)
+
+	if entering {
+		_, _ = w.WriteString(`<div class="markdown-alert markdown-alert-` + alert.alertType + `">` + "\n")
+		_, _ = w.WriteString(`<p class="markdown-alert-title">` + alertTypes[alert.alertType] + "</p>\n")
+	} else {
+		_, _ = w.WriteString("</div>\n")
+	}
+
+	return ast.WalkContinue, nil
+}
+
+func (githubRenderer) renderHeading(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	//nolint:forcetypeassert // only registered for ast.KindHeading
+	heading := node.(*ast.Heading)
+	tag := "h" + strconv.Itoa(heading.Level)
+
+	if !entering {
+		_, _ = w.WriteString("</" + tag + ">\n")
+		return ast.WalkContinue, nil
+	}
+
+	_, _ = w.WriteString("<" + tag)
+	if heading.Attributes() != nil {
+		htmlRenderer.RenderAttributes(w, heading, htmlRenderer.HeadingAttributeFilter)
+	}
+
+	_ = w.WriteByte('>')
+
+	if id, ok := heading.AttributeString("id"); ok {
+		if idBytes, ok := id.([]byte); ok {
+			href := util.EscapeHTML(util.URLEscape(append([]byte("#"), idBytes...), false))
+			_, _ = w.WriteString(`<a class="anchor" href="` + string(href) + `" aria-hidden="true">#</a>`)
+		}
+	}
+
+	return ast.WalkContinue, nil
+}
@@ -0,0 +1,137 @@
+package markdown_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anexia-it/go.anx.io/pkg/markdown"
+)
+
+func TestGitHubExtensions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		label       string
+		markdown    string
+		contains    []string
+		notContains []string
+	}{
+		{
+			"alert",
+			"> [!NOTE]\n> Useful information.\n",
+			[]string{`<div class="markdown-alert markdown-alert-note">`, `<p class="markdown-alert-title">Note</p>`, "<p>Useful information.</p>"},
+			[]string{"<blockquote>", "[!NOTE]"},
+		},
+		{
+			"lowercase alert",
+			"> [!warning]\n> Careful.\n",
+			[]string{`<div class="markdown-alert markdown-alert-warning">`, `<p class="markdown-alert-title">Warning</p>`},
+			nil,
+		},
+		{
+			"unknown alert",
+			"> [!FOO]\n> Bar.\n",
+			[]string{"<blockquote>", "[!FOO]"},
+			[]string{"markdown-alert"},
+		},
+		{
+			"marker not on its own line",
+			"> [!NOTE] inline\n",
+			[]string{"<blockquote>"},
+			[]string{"markdown-alert"},
+		},
+		{
+			"footnote",
+			"Text[^1]\n\n[^1]: The footnote.\n",
+			[]string{`<a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a>`, `<li id="fn:1">`},
+			nil,
+		},
+		{
+			"emoji",
+			"Release :tada: :+1:",
+			[]string{"Release 🎉 👍"},
+			[]string{":tada:"},
+		},
+		{
+			"unknown emoji and times",
+			"at 12:30:45 :not_an_emoji:",
+			[]string{"at 12:30:45 :not_an_emoji:"},
+			nil,
+		},
+		{
+			"emoji in code",
+			"`:tada:`",
+			[]string{"<code>:tada:</code>"},
+			nil,
+		},
+		{
+			"heading anchor",
+			"## Getting started",
+			[]string{`<h2 id="getting-started"><a class="anchor" href="#getting-started" aria-hidden="true">#</a>Getting started</h2>`},
+			nil,
+		},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			rendered, err := markdown.RenderMarkdown(testCase.markdown)
+			if err != nil {
+				t.Fatalf("error rendering markdown: %v", err)
+			}
+
+			for _, s := range testCase.contains {
+				if !strings.Contains(string(rendered), s) {
+					t.Errorf("expected %q in rendered markdown:\n%v", s, rendered)
+				}
+			}
+
+			for _, s := range testCase.notContains {
+				if strings.Contains(string(rendered), s) {
+					t.Errorf("did not expect %q in rendered markdown:\n%v", s, rendered)
+				}
+			}
+		})
+	}
+}
+
+func TestHeadingIDs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		label    string
+		markdown string
+		expected []string
+	}{
+		{"plain", "# Foo bar", []string{"foo-bar"}},
+		{"punctuation", "# What's new? (v1.2.0)", []string{"whats-new-v120"}},
+		{"underscores and hyphens", "# foo_bar - baz", []string{"foo_bar---baz"}},
+		{"inline markup", "# `RenderFile` and *friends*", []string{"renderfile-and-friends"}},
+		{"unicode", "# Über Größe", []string{"über-größe"}},
+		{"links", "# See [the docs](https://example.com/docs)", []string{"see-the-docs"}},
+		{"emoji", "# Usage :rocket:", []string{"usage-"}},
+		{"typographer", `# "Quoted" isn't changed`, []string{"quoted-isnt-changed"}},
+		{"duplicates", "# Usage\n## Usage\n### Usage", []string{"usage", "usage-1", "usage-2"}},
+		{"duplicate of suffixed", "# foo-1\n# foo\n# foo", []string{"foo-1", "foo", "foo-2"}},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			headings := markdown.ExtractHeadings(testCase.markdown)
+			if len(headings) != len(testCase.expected) {
+				t.Fatalf("expected %v headings, got %v", len(testCase.expected), len(headings))
+			}
+
+			for i, expected := range testCase.expected {
+				if headings[i].ID != expected {
+					t.Errorf("heading %v has id %q, expected %q", i, headings[i].ID, expected)
+				}
+			}
+		})
+	}
+}
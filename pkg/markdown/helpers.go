@@ -10,32 +10,70 @@ import (
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
+	htmlRenderer "github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/text"
 )
 
 var ErrNoHeadingFound = errors.New("no heading found")
 
-func RenderMarkdown(contents string) (template.HTML, error) {
-	highlighter := codeHighlighter()
+func RenderMarkdown(contents string, opts ...Option) (template.HTML, error) {
+	//nolint:exhaustruct // everything is optional
+	options := renderOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	markdown := newMarkdown(codeHighlighter(options))
 
-	markdown := goldmark.New(
+	buffer := bytes.Buffer{}
+	if err := markdown.Convert([]byte(contents), &buffer, parser.WithContext(newParserContext())); err != nil {
+		return "", fmt.Errorf("error processing markdown file to html: %w", err)
+	}
+
+	sanitized, err := sanitizeHTML(buffer.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("error sanitizing rendered markdown: %w", err)
+	}
+
+	// #nosec G203 -- sanitized above, raw HTML from the document is allowed by sanitizeHTML
+	return template.HTML(sanitized), nil
+}
+
+// newMarkdown creates the goldmark instance used for rendering and extracting information from
+// markdown documents, making sure we parse documents the same way for both.
+func newMarkdown(extensions ...goldmark.Extender) goldmark.Markdown {
+	return goldmark.New(
 		goldmark.WithExtensions(
-			extension.GFM,
-			extension.Typographer,
-			highlighter,
+			append([]goldmark.Extender{
+				extension.GFM,
+				extension.Typographer,
+				extension.Footnote,
+				githubExtensions{},
+				tableOfContents{},
+			}, extensions...)...,
 		),
-		goldmark.WithParserOptions(
-			parser.WithAutoHeadingID(),
+		goldmark.WithRendererOptions(
+			// RenderMarkdown sanitizes the output
+			htmlRenderer.WithUnsafe(),
 		),
 	)
+}
 
-	buffer := bytes.Buffer{}
-	if err := markdown.Convert([]byte(contents), &buffer); err != nil {
-		return "", fmt.Errorf("error processing markdown file to html: %w", err)
-	}
+// Heading is a heading in a markdown document.
+type Heading struct {
+	Level int
+	Text  string
+
+	// ID is the id attribute of the heading when rendered with RenderMarkdown.
+	ID string
+}
+
+// ExtractHeadings returns all headings in the given markdown document.
+func ExtractHeadings(contents string) []Heading {
+	source := []byte(contents)
+	doc := newMarkdown().Parser().Parse(text.NewReader(source), parser.WithContext(newParserContext()))
 
-	// #nosec
-	return template.HTML(buffer.Bytes()), nil
+	return extractHeadings(doc, source)
 }
 
 func ExtractFirstHeader(contents string) string {
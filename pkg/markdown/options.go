@@ -0,0 +1,39 @@
+package markdown
+
+// Option configures how RenderMarkdown renders a document.
+type Option func(*renderOptions)
+
+type renderOptions struct {
+	codeLinker CodeLinker
+	copyButton string
+}
+
+// CodeAnnotation marks a span of code in a code block to be rendered as link and/or anchor.
+type CodeAnnotation struct {
+	Offset int
+	Length int
+
+	// ID is set as id attribute, making it possible to link to the annotated code.
+	ID string
+
+	// Href is the link target for the annotated code.
+	Href string
+}
+
+// CodeLinker returns the annotations for the code of a code block in the given language.
+type CodeLinker func(language, code string) []CodeAnnotation
+
+// WithCodeLinker makes RenderMarkdown add links and anchors to code blocks, as returned by the given CodeLinker.
+func WithCodeLinker(linker CodeLinker) Option {
+	return func(o *renderOptions) {
+		o.codeLinker = linker
+	}
+}
+
+// WithCopyButton adds a button with the given label to every code block, for copying its code to the clipboard.
+// The button only has a class of copyCode, the actual copying has to be done in JavaScript.
+func WithCopyButton(label string) Option {
+	return func(o *renderOptions) {
+		o.copyButton = label
+	}
+}
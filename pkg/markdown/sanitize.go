@@ -0,0 +1,305 @@
+package markdown
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+	"golang.org/x/net/html"
+)
+
+// allowedTags are the elements kept when sanitizing, mostly the ones GitHub allows in READMEs and the
+// ones we render ourselves (code blocks, table of contents, ...).
+var allowedTags = setOf(
+	"a", "abbr", "b", "blockquote", "br", "button", "caption", "cite", "code", "col", "colgroup", "dd", "del",
+	"details", "dfn", "div", "dl", "dt", "em", "figcaption", "figure", "h1", "h2", "h3", "h4", "h5", "h6", "hr",
+	"i", "img", "input", "ins", "kbd", "li", "mark", "nav", "ol", "p", "picture", "pre", "q", "rp", "rt", "ruby",
+	"s", "samp", "small", "source", "span", "strike", "strong", "sub", "summary", "sup", "table", "tbody", "td",
+	"tfoot", "th", "thead", "time", "tr", "tt", "ul", "var", "wbr",
+)
+
+// droppedContentTags are elements removed together with their content, instead of only removing the tags.
+var droppedContentTags = setOf(
+	"embed", "iframe", "math", "noembed", "noframes", "noscript", "object", "script", "select", "style", "svg",
+	"template", "textarea", "title", "xmp",
+)
+
+// globalAttributes are allowed on every element in allowedTags.
+var globalAttributes = setOf(
+	"align", "aria-hidden", "aria-label", "class", "dir", "id", "lang", "role", "title",
+)
+
+var allowedAttributes = map[string]map[string]struct{}{
+	"a":          setOf("href", "name", "style"),
+	"blockquote": setOf("cite"),
+	"button":     setOf("type"),
+	"col":        setOf("span", "width"),
+	"colgroup":   setOf("span", "width"),
+	"del":        setOf("cite", "datetime"),
+	"details":    setOf("open"),
+	"img":        setOf("alt", "height", "loading", "sizes", "src", "srcset", "width"),
+	"input":      setOf("checked", "disabled", "type"),
+	"ins":        setOf("cite", "datetime"),
+	"li":         setOf("value"),
+	"ol":         setOf("reversed", "start", "type"),
+	"pre":        setOf("tabindex"),
+	"q":          setOf("cite"),
+	"source":     setOf("height", "media", "sizes", "srcset", "type", "width"),
+	"td":         setOf("colspan", "headers", "rowspan", "style"),
+	"th":         setOf("colspan", "headers", "rowspan", "scope", "style"),
+	"time":       setOf("datetime"),
+}
+
+// urlAttributes are checked to only contain relative URLs or ones with a scheme in allowedURLSchemes.
+var urlAttributes = setOf("cite", "href", "src")
+
+var allowedURLSchemes = setOf("http", "https", "mailto")
+
+// allowedStyleRegex matches the inline styles we render ourselves, used for table cell alignment and
+// the line number links in code blocks.
+var allowedStyleRegex = regexp.MustCompile(`^(\s*(text-align|outline|text-decoration|color)\s*:\s*[a-z-]+\s*;?)*\s*$`)
+
+// voidTags are elements without end tag.
+var voidTags = setOf("br", "col", "hr", "img", "input", "source", "wbr")
+
+func setOf(values ...string) map[string]struct{} {
+	ret := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		ret[v] = struct{}{}
+	}
+
+	return ret
+}
+
+func inSet(set map[string]struct{}, value string) bool {
+	_, ok := set[value]
+	return ok
+}
+
+// userContentPrefix is prepended to the id and name attributes in raw HTML of documents, like GitHub does, so
+// they cannot clash with the ids on our pages or clobber global variables of our scripts.
+const userContentPrefix = "user-content-"
+
+// rawHTMLRenderer renders raw HTML of documents with userContentPrefix added to ids and names. Since the whole
+// rendered document is sanitized afterwards, this is where we can still tell the ids of the document apart
+// from the ones we render ourselves.
+type rawHTMLRenderer struct{}
+
+func (r rawHTMLRenderer) RegisterFuncs(nrfr renderer.NodeRendererFuncRegisterer) {
+	nrfr.Register(ast.KindRawHTML, r.renderRawHTML)
+	nrfr.Register(ast.KindHTMLBlock, r.renderHTMLBlock)
+}
+
+func (rawHTMLRenderer) renderRawHTML(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+
+	//nolint:forcetypeassert // only registered for ast.KindRawHTML
+	segments := node.(*ast.RawHTML).Segments
+	raw := bytes.Buffer{}
+
+	for i := 0; i < segments.Len(); i++ {
+		segment := segments.At(i)
+		raw.Write(segment.Value(source))
+	}
+
+	prefixUserIDs(w, raw.Bytes())
+
+	return ast.WalkSkipChildren, nil
+}
+
+func (rawHTMLRenderer) renderHTMLBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	//nolint:forcetypeassert // only registered for ast.KindHTMLBlock
+	block := node.(*ast.HTMLBlock)
+	raw := bytes.Buffer{}
+
+	if entering {
+		for i := 0; i < block.Lines().Len(); i++ {
+			line := block.Lines().At(i)
+			raw.Write(line.Value(source))
+		}
+	} else if block.HasClosure() {
+		raw.Write(block.ClosureLine.Value(source))
+	}
+
+	prefixUserIDs(w, raw.Bytes())
+
+	return ast.WalkContinue, nil
+}
+
+// prefixUserIDs writes the raw HTML with userContentPrefix added to the id and name attributes, leaving
+// everything else as it is for sanitizeHTML.
+func prefixUserIDs(w util.BufWriter, raw []byte) {
+	tokenizer := html.NewTokenizer(bytes.NewReader(raw))
+
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			// an incomplete tag at the end would be completed by what follows, with its attributes unprefixed
+			_, _ = w.WriteString(html.EscapeString(string(tokenizer.Raw())))
+			return
+		}
+
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			_, _ = w.Write(tokenizer.Raw())
+			continue
+		}
+
+		rawTag := append([]byte{}, tokenizer.Raw()...)
+		token := tokenizer.Token()
+		prefixed := false
+
+		for i, attr := range token.Attr {
+			if attr.Namespace == "" && (attr.Key == "id" || attr.Key == "name") {
+				token.Attr[i].Val = userContentPrefix + attr.Val
+				prefixed = true
+			}
+		}
+
+		if prefixed {
+			_, _ = w.WriteString(token.String())
+		} else {
+			_, _ = w.Write(rawTag)
+		}
+	}
+}
+
+// sanitizeHTML removes everything not explicitly allowed from the given HTML, making it safe to include
+// HTML from READMEs in our pages. Elements not allowed are removed, keeping their content, except for the
+// ones in droppedContentTags. End tags are balanced, so the HTML cannot break out of the element we put it in.
+func sanitizeHTML(source []byte) ([]byte, error) {
+	tokenizer := html.NewTokenizer(bytes.NewReader(source))
+	ret := bytes.Buffer{}
+
+	openTags := make([]string, 0)
+	droppedDepth := 0
+
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			if err := tokenizer.Err(); !errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("error tokenizing html: %w", err)
+			}
+
+			break
+		}
+
+		token := tokenizer.Token()
+
+		if droppedDepth > 0 {
+			if inSet(droppedContentTags, token.Data) {
+				switch tokenType {
+				case html.StartTagToken:
+					droppedDepth++
+				case html.EndTagToken:
+					droppedDepth--
+				}
+			}
+
+			continue
+		}
+
+		switch tokenType {
+		case html.TextToken:
+			ret.WriteString(html.EscapeString(token.Data))
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if tokenType == html.StartTagToken && inSet(droppedContentTags, token.Data) {
+				droppedDepth++
+				continue
+			}
+
+			if !inSet(allowedTags, token.Data) {
+				continue
+			}
+
+			writeStartTag(&ret, token)
+
+			if tokenType == html.StartTagToken && !inSet(voidTags, token.Data) {
+				openTags = append(openTags, token.Data)
+			}
+
+		case html.EndTagToken:
+			// close everything opened after the element, end tags for elements not open are dropped
+			for i := len(openTags) - 1; i >= 0; i-- {
+				if openTags[i] != token.Data {
+					continue
+				}
+
+				for j := len(openTags) - 1; j >= i; j-- {
+					ret.WriteString("</" + openTags[j] + ">")
+				}
+
+				openTags = openTags[:i]
+
+				break
+			}
+
+		case html.CommentToken, html.DoctypeToken, html.ErrorToken:
+			continue
+		}
+	}
+
+	for i := len(openTags) - 1; i >= 0; i-- {
+		ret.WriteString("</" + openTags[i] + ">")
+	}
+
+	return ret.Bytes(), nil
+}
+
+func writeStartTag(w *bytes.Buffer, token html.Token) {
+	w.WriteString("<" + token.Data)
+
+	for _, attr := range token.Attr {
+		if attr.Namespace != "" || !allowedAttribute(token.Data, attr.Key, attr.Val) {
+			continue
+		}
+
+		w.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+	}
+
+	if token.Type == html.SelfClosingTagToken {
+		w.WriteString(" /")
+	}
+
+	w.WriteByte('>')
+}
+
+func allowedAttribute(tag, name, value string) bool {
+	if !inSet(globalAttributes, name) && !inSet(allowedAttributes[tag], name) {
+		return false
+	}
+
+	switch {
+	case name == "style":
+		return allowedStyleRegex.MatchString(value)
+	case name == "srcset":
+		for _, candidate := range strings.Split(value, ",") {
+			if fields := strings.Fields(candidate); len(fields) > 0 && !allowedURL(fields[0]) {
+				return false
+			}
+		}
+	case inSet(urlAttributes, name):
+		return allowedURL(value)
+	}
+
+	return true
+}
+
+// allowedURL returns if the given URL is relative or has a scheme in allowedURLSchemes.
+func allowedURL(value string) bool {
+	u, err := url.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return false
+	}
+
+	return u.Scheme == "" || inSet(allowedURLSchemes, strings.ToLower(u.Scheme))
+}
@@ -0,0 +1,146 @@
+package markdown_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anexia-it/go.anx.io/pkg/markdown"
+)
+
+func TestRawHTMLSanitization(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		label       string
+		markdown    string
+		contains    []string
+		notContains []string
+	}{
+		{
+			"details",
+			"<details>\n<summary>More</summary>\n\nHidden *text*\n\n</details>\n",
+			[]string{"<details>", "<summary>More</summary>", "<em>text</em>", "</details>"},
+			nil,
+		},
+		{
+			"image size",
+			`<img src="logo.png" width="100" alt="Logo" onerror="alert(1)">`,
+			[]string{`<img src="logo.png" width="100" alt="Logo">`},
+			[]string{"onerror"},
+		},
+		{
+			"picture",
+			"<picture>\n" +
+				`  <source media="(prefers-color-scheme: dark)" srcset="dark.png 1x, dark@2x.png 2x">` + "\n" +
+				`  <img src="light.png">` + "\n" +
+				"</picture>\n",
+			[]string{"<picture>", `<source media="(prefers-color-scheme: dark)" srcset="dark.png 1x, dark@2x.png 2x">`, "</picture>"},
+			nil,
+		},
+		{
+			"script",
+			"foo <script>alert(1)</script> bar",
+			[]string{"foo  bar"},
+			[]string{"script", "alert"},
+		},
+		{
+			"dropped content",
+			"<iframe src=\"https://example.com\">\nfallback\n</iframe>\n\n<svg><style>*{}</style><text>svg</text></svg>after",
+			[]string{"after"},
+			[]string{"iframe", "fallback", "svg", "style"},
+		},
+		{
+			"disallowed element keeps content",
+			`<form action="/login"><b>bold</b></form>`,
+			[]string{"<b>bold</b>"},
+			[]string{"form", "action"},
+		},
+		{
+			"javascript link",
+			`<a href="javascript:alert(1)">x</a> [y](javascript:alert(2)) <a href=" JaVaScRiPt:alert(3)">z</a>`,
+			[]string{"<a>x</a>", "<a>y</a>", "<a>z</a>"},
+			[]string{"javascript", "JaVaScRiPt"},
+		},
+		{
+			"encoded javascript link",
+			`<a href="javascript&colon;alert(1)">x</a> <a href="java&#x09;script:alert(2)">y</a>`,
+			[]string{"<a>x</a>", "<a>y</a>"},
+			[]string{"alert"},
+		},
+		{
+			"allowed links",
+			`<a href="https://example.com/?a=1&b=2">x</a> <a href="mailto:foo@example.com">y</a> <a href="#usage">z</a>`,
+			[]string{`href="https://example.com/?a=1&amp;b=2"`, `href="mailto:foo@example.com"`, `href="#usage"`},
+			nil,
+		},
+		{
+			"srcset",
+			`<img srcset="a.png 1x, javascript:alert(1) 2x">`,
+			[]string{"<img>"},
+			[]string{"srcset"},
+		},
+		{
+			"style",
+			`<span style="background: url(https://example.com/track)">x</span>`,
+			[]string{"<span>x</span>"},
+			[]string{"style", "url("},
+		},
+		{
+			"unbalanced tags",
+			"<div><b>open\n\n</span></div></div></main>",
+			[]string{"<div><b>open\n", "</b></div>"},
+			[]string{"</div></div>", "</span>", "</main>"},
+		},
+		{
+			"unclosed tags",
+			"<div>\n\n*foo*",
+			[]string{"<div>\n<p><em>foo</em></p>\n</div>"},
+			nil,
+		},
+		{
+			"comments",
+			"foo <!-- secret --> bar",
+			[]string{"foo  bar"},
+			[]string{"secret"},
+		},
+		{
+			"user ids",
+			"# Usage\n\n<div\n  id=\"usage\"><a name=\"install\"></a></div>\n\n[Install](#install) <span id=\"inline\">x</span>",
+			[]string{
+				`<h1 id="usage">`, `<div id="user-content-usage">`, `<a name="user-content-install">`,
+				`<span id="user-content-inline">x</span>`, `href="#install"`,
+			},
+			[]string{`<div id="usage"`, `name="install"`, `id="inline"`},
+		},
+		{
+			"incomplete tag",
+			"<div>\n<span id=\"a\"\n\nafter <b>bold</b>",
+			[]string{"&lt;span id=&#34;a&#34;", "after <b>bold</b>"},
+			[]string{`<span`},
+		},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			rendered, err := markdown.RenderMarkdown(testCase.markdown)
+			if err != nil {
+				t.Fatalf("error rendering markdown: %v", err)
+			}
+
+			for _, s := range testCase.contains {
+				if !strings.Contains(string(rendered), s) {
+					t.Errorf("expected %q in rendered markdown:\n%v", s, rendered)
+				}
+			}
+
+			for _, s := range testCase.notContains {
+				if strings.Contains(string(rendered), s) {
+					t.Errorf("did not expect %q in rendered markdown:\n%v", s, rendered)
+				}
+			}
+		})
+	}
+}
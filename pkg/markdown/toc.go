@@ -0,0 +1,180 @@
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// tocMarker is replaced with the table of contents when it is on a line of its own in a document.
+const tocMarker = "<!-- toc -->"
+
+// TOCEntry is a heading in the table of contents, with the headings of its section as children.
+type TOCEntry struct {
+	Heading
+
+	Children []*TOCEntry
+}
+
+// ExtractTableOfContents returns the table of contents of the given markdown document, with the same
+// ids for the headings as RenderMarkdown gives them.
+func ExtractTableOfContents(contents string) []*TOCEntry {
+	return buildTableOfContents(ExtractHeadings(contents))
+}
+
+// buildTableOfContents makes a tree from the list of headings, every heading being a child of the
+// previous heading with a lower level.
+func buildTableOfContents(headings []Heading) []*TOCEntry {
+	ret := make([]*TOCEntry, 0)
+	parents := make([]*TOCEntry, 0)
+
+	for _, heading := range headings {
+		entry := &TOCEntry{Heading: heading, Children: nil}
+
+		for len(parents) > 0 && parents[len(parents)-1].Level >= heading.Level {
+			parents = parents[:len(parents)-1]
+		}
+
+		if len(parents) == 0 {
+			ret = append(ret, entry)
+		} else {
+			parent := parents[len(parents)-1]
+			parent.Children = append(parent.Children, entry)
+		}
+
+		parents = append(parents, entry)
+	}
+
+	return ret
+}
+
+// extractHeadings returns all headings in the given document, which has to be parsed with the heading ids set.
+func extractHeadings(doc ast.Node, source []byte) []Heading {
+	ret := make([]Heading, 0)
+
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if heading, ok := node.(*ast.Heading); ok && entering {
+			id, _ := heading.AttributeString("id")
+			idBytes, _ := id.([]byte)
+
+			ret = append(ret, Heading{
+				Level: heading.Level,
+				Text:  plainText(heading, source),
+				ID:    string(idBytes),
+			})
+
+			return ast.WalkSkipChildren, nil
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return ret
+}
+
+var kindTableOfContents = ast.NewNodeKind("TableOfContents")
+
+// tocNode is the table of contents placed where the document has the tocMarker.
+type tocNode struct {
+	ast.BaseBlock
+
+	entries []*TOCEntry
+}
+
+func (n *tocNode) Kind() ast.NodeKind {
+	return kindTableOfContents
+}
+
+func (n *tocNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// tableOfContents replaces the tocMarker in documents with the table of contents.
+type tableOfContents struct{}
+
+func (t tableOfContents) Extend(md goldmark.Markdown) {
+	md.Parser().AddOptions(
+		parser.WithASTTransformers(
+			// after the headingIDTransformer
+			util.PrioritizedValue{Value: t, Priority: 100},
+		),
+	)
+
+	md.Renderer().AddOptions(
+		renderer.WithNodeRenderers(
+			util.PrioritizedValue{Value: t, Priority: 0},
+		),
+	)
+}
+
+func (tableOfContents) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+
+	var entries []*TOCEntry
+
+	for node := doc.FirstChild(); node != nil; node = node.NextSibling() {
+		block, ok := node.(*ast.HTMLBlock)
+		if !ok || block.Lines().Len() != 1 {
+			continue
+		}
+
+		line := block.Lines().At(0)
+		if !strings.EqualFold(string(bytes.TrimSpace(line.Value(source))), tocMarker) {
+			continue
+		}
+
+		if entries == nil {
+			entries = buildTableOfContents(extractHeadings(doc, source))
+		}
+
+		toc := &tocNode{BaseBlock: ast.BaseBlock{}, entries: entries}
+		doc.ReplaceChild(doc, block, toc)
+		node = toc
+	}
+}
+
+func (t tableOfContents) RegisterFuncs(nrfr renderer.NodeRendererFuncRegisterer) {
+	nrfr.Register(kindTableOfContents, t.render)
+}
+
+func (tableOfContents) render(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		//nolint:forcetypeassert // only registered for kindTableOfContents
+		entries := node.(*tocNode).entries
+
+		_, _ = w.WriteString(`<nav class="toc">` + "\n")
+		writeTOCEntries(w, entries)
+		_, _ = w.WriteString("</nav>\n")
+	}
+
+	return ast.WalkContinue, nil
+}
+
+func writeTOCEntries(w util.BufWriter, entries []*TOCEntry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	_, _ = w.WriteString("<ul>\n")
+
+	for _, entry := range entries {
+		href := util.EscapeHTML(util.URLEscape([]byte("#"+entry.ID), false))
+		_, _ = w.WriteString(`<li><a href="` + string(href) + `">` + html.EscapeString(entry.Text) + "</a>")
+
+		if len(entry.Children) > 0 {
+			_ = w.WriteByte('\n')
+			writeTOCEntries(w, entry.Children)
+		}
+
+		_, _ = w.WriteString("</li>\n")
+	}
+
+	_, _ = w.WriteString("</ul>\n")
+}
@@ -0,0 +1,85 @@
+package markdown_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anexia-it/go.anx.io/pkg/markdown"
+)
+
+func TestExtractTableOfContents(t *testing.T) {
+	t.Parallel()
+
+	// formatTOC returns the table of contents as ids, with the children in parentheses
+	var formatTOC func(entries []*markdown.TOCEntry) string
+	formatTOC = func(entries []*markdown.TOCEntry) string {
+		ret := make([]string, 0, len(entries))
+
+		for _, entry := range entries {
+			if len(entry.Children) > 0 {
+				ret = append(ret, entry.ID+"("+formatTOC(entry.Children)+")")
+			} else {
+				ret = append(ret, entry.ID)
+			}
+		}
+
+		return strings.Join(ret, " ")
+	}
+
+	testCases := []struct {
+		label    string
+		markdown string
+		expected string
+	}{
+		{"no headings", "foo bar", ""},
+		{"flat", "## a\n## b\n## c", "a b c"},
+		{"nested", "# title\n## a\n### a1\n### a2\n## b", "title(a(a1 a2) b)"},
+		{"skipped level", "# title\n### a\n## b\n#### b1", "title(a b(b1))"},
+		{"starting deeper", "### a\n# b\n## c", "a b(c)"},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			actual := formatTOC(markdown.ExtractTableOfContents(testCase.markdown))
+			if actual != testCase.expected {
+				t.Errorf("%q (actual) did not match %q (expected)", actual, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestInlineTableOfContents(t *testing.T) {
+	t.Parallel()
+
+	rendered, err := markdown.RenderMarkdown("# Title\n\n<!-- toc -->\n\n## What's new\n### Details\n## Usage\n")
+	if err != nil {
+		t.Fatalf("error rendering markdown: %v", err)
+	}
+
+	expected := `<nav class="toc">
+<ul>
+<li><a href="#title">Title</a>
+<ul>
+<li><a href="#whats-new">What’s new</a>
+<ul>
+<li><a href="#details">Details</a></li>
+</ul>
+</li>
+<li><a href="#usage">Usage</a></li>
+</ul>
+</li>
+</ul>
+</nav>
+`
+
+	if !strings.Contains(string(rendered), expected) {
+		t.Errorf("expected table of contents in rendered markdown:\n%v", rendered)
+	}
+
+	if strings.Contains(string(rendered), "raw HTML omitted") {
+		t.Errorf("marker was not replaced:\n%v", rendered)
+	}
+}
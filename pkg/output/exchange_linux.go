@@ -0,0 +1,11 @@
+package output
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// exchange atomically swaps the directories a and b.
+func exchange(a, b string) error {
+	//nolint:wrapcheck // wrapped by caller
+	return unix.Renameat2(unix.AT_FDCWD, a, unix.AT_FDCWD, b, unix.RENAME_EXCHANGE)
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package output
+
+import (
+	"errors"
+)
+
+var errExchangeUnsupported = errors.New("atomically exchanging directories is not supported on this system")
+
+// exchange atomically swaps the directories a and b, which we only can do on linux.
+func exchange(_, _ string) error {
+	return errExchangeUnsupported
+}
@@ -0,0 +1,154 @@
+// Package output writes generated files into a temporary directory next to the destination, replacing the
+// destination only after everything was written, so a failed build never leaves a half-written site behind.
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Write calls write with a new temporary directory next to destination and replaces destination with it when
+// write succeeds. Files in destination not written again are kept, unless prune is set.
+func Write(destination string, prune bool, write func(dir string) error) error {
+	destination = filepath.Clean(destination)
+
+	if stat, err := os.Stat(destination); err == nil && !stat.IsDir() {
+		return fmt.Errorf("destination %q is not a directory", destination)
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error checking destination %q: %w", destination, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destination), 0755); err != nil {
+		return fmt.Errorf("error creating parent directory of %q: %w", destination, err)
+	}
+
+	// the temporary directory has to be on the same file system as the destination for renaming it
+	tempDir, err := os.MkdirTemp(filepath.Dir(destination), "."+filepath.Base(destination)+"-")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %w", err)
+	}
+
+	// after successfully swapping, tempDir holds the previous contents of destination
+	defer os.RemoveAll(tempDir)
+
+	if err := os.Chmod(tempDir, 0755); err != nil {
+		return fmt.Errorf("error setting permissions of temporary directory: %w", err)
+	}
+
+	if err := write(tempDir); err != nil {
+		return err
+	}
+
+	if !prune {
+		if err := keepStaleFiles(destination, tempDir); err != nil {
+			return err
+		}
+	}
+
+	if err := swap(tempDir, destination); err != nil {
+		return fmt.Errorf("error replacing %q with the newly generated files: %w", destination, err)
+	}
+
+	return nil
+}
+
+// keepStaleFiles links or copies every file in destination not existing in tempDir into it.
+func keepStaleFiles(destination, tempDir string) error {
+	err := filepath.WalkDir(destination, func(filePath string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && filePath == destination {
+			return fs.SkipAll
+		} else if err != nil {
+			return err
+		}
+
+		relative, err := filepath.Rel(destination, filePath)
+		if err != nil {
+			return fmt.Errorf("error getting relative path of %q: %w", filePath, err)
+		}
+
+		target := filepath.Join(tempDir, relative)
+
+		if _, err := os.Lstat(target); err == nil {
+			return nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("error checking %q: %w", target, err)
+		}
+
+		if d.IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return fmt.Errorf("error creating directory %q: %w", target, err)
+			}
+
+			return nil
+		}
+
+		if err := os.Link(filePath, target); err == nil {
+			return nil
+		}
+
+		return copyFile(filePath, target)
+	})
+
+	if err != nil {
+		return fmt.Errorf("error keeping files of previous build: %w", err)
+	}
+
+	return nil
+}
+
+func copyFile(source, destination string) error {
+	sourceFile, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("error opening %q: %w", source, err)
+	}
+
+	defer sourceFile.Close()
+
+	destinationFile, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return fmt.Errorf("error creating %q: %w", destination, err)
+	}
+
+	if _, err := io.Copy(destinationFile, sourceFile); err != nil {
+		_ = destinationFile.Close()
+		return fmt.Errorf("error copying %q to %q: %w", source, destination, err)
+	}
+
+	if err := destinationFile.Close(); err != nil {
+		return fmt.Errorf("error writing %q: %w", destination, err)
+	}
+
+	return nil
+}
+
+// swap moves newDir to destination, moving the previous destination to newDir. It is only done
+// atomically on systems supporting it, otherwise destination is missing for a short moment.
+func swap(newDir, destination string) error {
+	if _, err := os.Stat(destination); errors.Is(err, fs.ErrNotExist) {
+		//nolint:wrapcheck // wrapped by caller
+		return os.Rename(newDir, destination)
+	}
+
+	if err := exchange(newDir, destination); err == nil {
+		return nil
+	}
+
+	previous := newDir + ".previous"
+
+	if err := os.Rename(destination, previous); err != nil {
+		return fmt.Errorf("error moving %q out of the way: %w", destination, err)
+	}
+
+	if err := os.Rename(newDir, destination); err != nil {
+		// try to get the previous files back in place
+		_ = os.Rename(previous, destination)
+		return fmt.Errorf("error moving %q to %q: %w", newDir, destination, err)
+	}
+
+	//nolint:wrapcheck // wrapped by caller
+	return os.RemoveAll(previous)
+}
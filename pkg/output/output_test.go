@@ -0,0 +1,122 @@
+package output_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anexia-it/go.anx.io/pkg/output"
+)
+
+var errWrite = errors.New("write failed")
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, contents := range files {
+		filePath := filepath.Join(dir, name)
+
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatalf("error creating directory: %v", err)
+		}
+
+		if err := os.WriteFile(filePath, []byte(contents), 0644); err != nil {
+			t.Fatalf("error writing file: %v", err)
+		}
+	}
+}
+
+func readFiles(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	ret := make(map[string]string)
+
+	err := filepath.WalkDir(dir, func(filePath string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		contents, err := os.ReadFile(filePath)
+		relative, _ := filepath.Rel(dir, filePath)
+		ret[filepath.ToSlash(relative)] = string(contents)
+
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error reading files: %v", err)
+	}
+
+	return ret
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	previous := map[string]string{"index.html": "old index", "pkg/v1.0.0/index.html": "removed version"}
+	generated := map[string]string{"index.html": "new index", "pkg/v1.1.0/index.html": "new version"}
+
+	testCases := []struct {
+		label       string
+		destination string
+		existing    map[string]string
+		prune       bool
+		writeErr    error
+		expected    map[string]string
+	}{
+		{"new destination", "public", nil, false, nil, generated},
+		{"missing parent directory", "build/site/public", nil, false, nil, generated},
+		{
+			"keeping stale files",
+			"public",
+			previous,
+			false,
+			nil,
+			map[string]string{"index.html": "new index", "pkg/v1.0.0/index.html": "removed version", "pkg/v1.1.0/index.html": "new version"},
+		},
+		{"pruning stale files", "public", previous, true, nil, generated},
+		{"failed write", "public", previous, true, errWrite, previous},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			destination := filepath.Join(t.TempDir(), filepath.FromSlash(testCase.destination))
+
+			if testCase.existing != nil {
+				writeFiles(t, destination, testCase.existing)
+			}
+
+			err := output.Write(destination, testCase.prune, func(dir string) error {
+				writeFiles(t, dir, generated)
+				return testCase.writeErr
+			})
+
+			if !errors.Is(err, testCase.writeErr) {
+				t.Errorf("expected error %v, got %v", testCase.writeErr, err)
+			}
+
+			actual := readFiles(t, destination)
+			if len(actual) != len(testCase.expected) {
+				t.Errorf("expected files %v, got %v", testCase.expected, actual)
+			}
+
+			for name, contents := range testCase.expected {
+				if actual[name] != contents {
+					t.Errorf("file %q has contents %q, expected %q", name, actual[name], contents)
+				}
+			}
+
+			entries, err := os.ReadDir(filepath.Dir(destination))
+			if err != nil {
+				t.Fatalf("error listing parent directory: %v", err)
+			}
+
+			if len(entries) != 1 {
+				t.Errorf("expected temporary directories to be removed, found %v entries", len(entries))
+			}
+		})
+	}
+}
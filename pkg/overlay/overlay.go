@@ -0,0 +1,126 @@
+// Package overlay implements a read-only fs.FS layering multiple file systems over each other.
+package overlay
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+)
+
+// FS serves every file from the first of its layers containing it, directory listings are merged.
+type FS struct {
+	layers []fs.FS
+}
+
+// New creates an overlay of the given layers, earlier layers taking precedence over later ones.
+func New(layers ...fs.FS) *FS {
+	return &FS{layers: layers}
+}
+
+// Open implements fs.FS.
+func (o *FS) Open(name string) (fs.File, error) {
+	for _, layer := range o.layers {
+		file, err := layer.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("error opening %q: %w", name, err)
+		}
+
+		if stat, err := file.Stat(); err != nil || !stat.IsDir() {
+			return file, nil
+		}
+
+		// directories have to list the entries of all layers
+		entries, err := o.ReadDir(name)
+		if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+
+		return &dir{File: file, entries: entries}, nil
+	}
+
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadFile implements fs.ReadFileFS.
+func (o *FS) ReadFile(name string) ([]byte, error) {
+	for _, layer := range o.layers {
+		contents, err := fs.ReadFile(layer, name)
+		if err == nil {
+			return contents, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("error reading %q: %w", name, err)
+		}
+	}
+
+	return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir implements fs.ReadDirFS, merging the entries of the directory in all layers, sorted by name.
+func (o *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries := make(map[string]fs.DirEntry)
+	found := false
+
+	for _, layer := range o.layers {
+		layerEntries, err := fs.ReadDir(layer, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("error reading directory %q: %w", name, err)
+		}
+
+		found = true
+
+		for _, entry := range layerEntries {
+			if _, ok := entries[entry.Name()]; !ok {
+				entries[entry.Name()] = entry
+			}
+		}
+	}
+
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	ret := make([]fs.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		ret = append(ret, entry)
+	}
+
+	sort.Slice(ret, func(a, b int) bool {
+		return ret[a].Name() < ret[b].Name()
+	})
+
+	return ret, nil
+}
+
+// dir is an opened directory of the overlay, listing the merged entries of all layers.
+type dir struct {
+	fs.File
+
+	entries []fs.DirEntry
+}
+
+// ReadDir implements fs.ReadDirFile.
+func (d *dir) ReadDir(count int) ([]fs.DirEntry, error) {
+	if count <= 0 {
+		ret := d.entries
+		d.entries = nil
+
+		return ret, nil
+	}
+
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+
+	count = min(count, len(d.entries))
+	ret := d.entries[:count]
+	d.entries = d.entries[count:]
+
+	return ret, nil
+}
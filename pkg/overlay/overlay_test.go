@@ -0,0 +1,63 @@
+package overlay_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/anexia-it/go.anx.io/pkg/overlay"
+)
+
+func TestFS(t *testing.T) {
+	t.Parallel()
+
+	upper := fstest.MapFS{
+		"layout.tmpl":     {Data: []byte("upper layout")},
+		"dir/upper.txt":   {Data: []byte("upper")},
+		"onlyupper/a.txt": {Data: []byte("a")},
+	}
+
+	lower := fstest.MapFS{
+		"layout.tmpl":   {Data: []byte("lower layout")},
+		"main.tmpl":     {Data: []byte("lower main")},
+		"dir/lower.txt": {Data: []byte("lower")},
+	}
+
+	fsys := overlay.New(upper, lower)
+
+	testCases := []struct {
+		file     string
+		contents string
+	}{
+		{"layout.tmpl", "upper layout"},
+		{"main.tmpl", "lower main"},
+		{"dir/lower.txt", "lower"},
+	}
+
+	for _, testCase := range testCases {
+		contents, err := fs.ReadFile(fsys, testCase.file)
+		if err != nil {
+			t.Errorf("error reading %q: %v", testCase.file, err)
+		} else if string(contents) != testCase.contents {
+			t.Errorf("%q (actual) did not match %q (expected)", contents, testCase.contents)
+		}
+	}
+
+	matches, err := fs.Glob(fsys, "dir/*.txt")
+	if err != nil {
+		t.Fatalf("error globbing: %v", err)
+	}
+
+	if len(matches) != 2 || matches[0] != "dir/lower.txt" || matches[1] != "dir/upper.txt" {
+		t.Errorf("expected merged directory listing, got %v", matches)
+	}
+
+	if _, err := fs.ReadFile(fsys, "missing.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected not exist error, got %v", err)
+	}
+
+	if err := fstest.TestFS(fsys, "layout.tmpl", "main.tmpl", "dir/upper.txt", "dir/lower.txt", "onlyupper/a.txt"); err != nil {
+		t.Errorf("file system does not behave correctly: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+const (
+	apiPathPrefix     = "api/"
+	apiPackagesFile   = apiPathPrefix + "packages.json"
+	apiPackagesPrefix = apiPathPrefix + "packages/"
+)
+
+type apiPackage struct {
+	TargetName string            `json:"targetName"`
+	ImportPath string            `json:"importPath"`
+	Source     string            `json:"source"`
+	Summary    string            `json:"summary"`
+	URL        string            `json:"url"`
+	Majors     []apiMajorVersion `json:"majors"`
+}
+
+type apiMajorVersion struct {
+	Major      string `json:"major"`
+	ImportPath string `json:"importPath"`
+
+	// Latest is the latest release, or the latest version if the major version has no releases yet.
+	Latest string `json:"latest"`
+
+	// Versions is only included in the per-package documents.
+	Versions []apiVersion `json:"versions,omitempty"`
+}
+
+type apiVersion struct {
+	Version    string    `json:"version"`
+	Commit     string    `json:"commit"`
+	Date       time.Time `json:"date"`
+	CommitDate time.Time `json:"commitDate"`
+	IsBranch   bool      `json:"isBranch"`
+	TagMessage string    `json:"tagMessage,omitempty"`
+	URL        string    `json:"url"`
+}
+
+// apiPackageFile returns the path of the JSON document describing the given package.
+func apiPackageFile(pkg *types.Package) string {
+	return apiPackagesPrefix + pkg.TargetName + ".json"
+}
+
+func (r *Renderer) filesForAPI() []string {
+	ret := []string{apiPackagesFile}
+
+	for _, pkg := range r.packages {
+		ret = append(ret, apiPackageFile(pkg))
+	}
+
+	return ret
+}
+
+func (r *Renderer) renderAPIFile(filePath string, writer io.Writer) error {
+	var document interface{}
+
+	if filePath == apiPackagesFile {
+		packages := make([]apiPackage, 0, len(r.packages))
+
+		for _, pkg := range r.packages {
+			p, err := r.apiPackage(pkg, false)
+			if err != nil {
+				return err
+			}
+
+			packages = append(packages, p)
+		}
+
+		document = packages
+	} else {
+		for _, pkg := range r.packages {
+			if filePath == apiPackageFile(pkg) {
+				p, err := r.apiPackage(pkg, true)
+				if err != nil {
+					return err
+				}
+
+				document = p
+			}
+		}
+	}
+
+	if document == nil {
+		return fmt.Errorf("%w: unknown API file %q", ErrNotFound, filePath)
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("error encoding API file %q: %w", filePath, err)
+	}
+
+	return nil
+}
+
+func (r *Renderer) apiPackage(pkg *types.Package, withVersions bool) (apiPackage, error) {
+	ret := apiPackage{
+		TargetName: pkg.TargetName,
+		ImportPath: path.Join("go.anx.io", pkg.TargetName),
+		Source:     pkg.Source,
+		Summary:    pkg.Summary,
+		URL:        r.baseURL + "/" + pkg.TargetName + "/",
+		Majors:     make([]apiMajorVersion, 0),
+	}
+
+	for _, major := range pkg.FileReader.MajorVersions() {
+		latest, err := latestVersion(pkg, major)
+		if err != nil {
+			return ret, err
+		}
+
+		majorVersion := apiMajorVersion{
+			Major:      major,
+			ImportPath: path.Join("go.anx.io", pkg.TargetName, major),
+			Latest:     latest,
+			Versions:   nil,
+		}
+
+		if withVersions {
+			allVersions := pkg.FileReader.Versions(major)
+			majorVersion.Versions = make([]apiVersion, 0, len(allVersions))
+
+			for _, version := range allVersions {
+				info, err := pkg.FileReader.VersionInfo(version)
+				if err != nil {
+					return ret, fmt.Errorf("error retrieving info for version %q: %w", version, err)
+				}
+
+				majorVersion.Versions = append(majorVersion.Versions, apiVersion{
+					Version:    version,
+					Commit:     info.Commit,
+					Date:       info.Date.UTC(),
+					CommitDate: info.CommitDate.UTC(),
+					IsBranch:   info.IsBranch,
+					TagMessage: strings.TrimSpace(info.TagMessage),
+					URL:        r.baseURL + canonicalPackagePath(pkg, major, "README.md@"+version),
+				})
+			}
+		}
+
+		ret.Majors = append(ret.Majors, majorVersion)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,105 @@
+package render_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/anexia-it/go.anx.io/pkg/render"
+)
+
+func TestAPIFiles(t *testing.T) {
+	t.Parallel()
+
+	multi := packageWithVersions("multi", map[string][]string{"": {"v1.0.0"}, "v2": {"v2.0.0"}})
+
+	renderer := newRenderer(t, multi)
+	renderer.SetBaseURL("https://go.anx.io/")
+
+	testCases := []struct {
+		file     string
+		expected string
+	}{
+		{
+			"api/packages.json",
+			`[{
+				"targetName": "multi",
+				"importPath": "go.anx.io/multi",
+				"source": "https://github.com/anexia/go-example.git",
+				"summary": "Example package",
+				"url": "https://go.anx.io/multi/",
+				"majors": [
+					{"major": "v2", "importPath": "go.anx.io/multi/v2", "latest": "v2.0.0"},
+					{"major": "", "importPath": "go.anx.io/multi", "latest": "v1.0.0"}
+				]
+			}]`,
+		},
+		{
+			"api/packages/multi.json",
+			`{
+				"targetName": "multi",
+				"importPath": "go.anx.io/multi",
+				"source": "https://github.com/anexia/go-example.git",
+				"summary": "Example package",
+				"url": "https://go.anx.io/multi/",
+				"majors": [
+					{
+						"major": "v2", "importPath": "go.anx.io/multi/v2", "latest": "v2.0.0",
+						"versions": [{
+							"version": "v2.0.0",
+							"commit": "0000000000000000000000000000000000000002",
+							"date": "2024-01-10T12:00:00Z",
+							"commitDate": "2024-01-10T12:00:00Z",
+							"isBranch": false,
+							"tagMessage": "Release v2.0.0",
+							"url": "https://go.anx.io/multi/v2/README.md@v2.0.0"
+						}]
+					},
+					{
+						"major": "", "importPath": "go.anx.io/multi", "latest": "v1.0.0",
+						"versions": [{
+							"version": "v1.0.0",
+							"commit": "0000000000000000000000000000000000000001",
+							"date": "2024-01-09T12:00:00Z",
+							"commitDate": "2024-01-09T12:00:00Z",
+							"isBranch": false,
+							"tagMessage": "Release v1.0.0",
+							"url": "https://go.anx.io/multi/README.md@v1.0.0"
+						}]
+					}
+				]
+			}`,
+		},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.file, func(t *testing.T) {
+			t.Parallel()
+
+			buffer := bytes.Buffer{}
+			if err := renderer.RenderFile(nil, testCase.file, &buffer); err != nil {
+				t.Fatalf("error rendering %v: %v", testCase.file, err)
+			}
+
+			var actual, expected interface{}
+			if err := json.Unmarshal(buffer.Bytes(), &actual); err != nil {
+				t.Fatalf("error decoding %v: %v", testCase.file, err)
+			}
+
+			if err := json.Unmarshal([]byte(testCase.expected), &expected); err != nil {
+				t.Fatalf("error decoding expected document: %v", err)
+			}
+
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("%v (actual) did not match %v (expected)", buffer.String(), testCase.expected)
+			}
+		})
+	}
+
+	if err := renderer.RenderFile(nil, "api/packages/unknown.json", &bytes.Buffer{}); !errors.Is(err, render.ErrNotFound) {
+		t.Errorf("expected not found for unknown package, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package render
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/anexia-it/go.anx.io/pkg/markdown"
+)
+
+// chromaStylesheet is the path of the generated stylesheet for code highlighting.
+const chromaStylesheet = "chroma/style.css"
+
+// fingerprintLength is the number of hex digits of the content hash in fingerprinted file names.
+const fingerprintLength = 10
+
+var fingerprintRegex = regexp.MustCompile(`^(.*)\.[0-9a-f]{10}(\.[^./]+)$`)
+
+// Fingerprint returns the file name with a hash of its contents inserted before the extension, e.g.
+// `static/style.0123456789.css`. Fingerprinted files can be cached forever, since changed contents get a new name.
+func Fingerprint(name string, contents []byte) string {
+	hash := sha256.Sum256(contents)
+	ext := path.Ext(name)
+
+	return fmt.Sprintf("%v.%v%v", strings.TrimSuffix(name, ext), hex.EncodeToString(hash[:])[:fingerprintLength], ext)
+}
+
+// StripFingerprint returns the file name without fingerprint, false if it has none.
+func StripFingerprint(name string) (string, bool) {
+	match := fingerprintRegex.FindStringSubmatch(name)
+	if match == nil {
+		return name, false
+	}
+
+	return match[1] + match[2], true
+}
+
+// SetStaticFiles sets the static files the `asset` template function creates fingerprinted URLs for.
+func (r *Renderer) SetStaticFiles(staticFiles fs.FS) {
+	r.assetMutex.Lock()
+	defer r.assetMutex.Unlock()
+
+	r.staticFiles = staticFiles
+	r.assetCache = make(map[string]string)
+}
+
+// SetAssetCaching enables or disables caching the fingerprinted URLs of assets, enabled by default. When serving,
+// caching is disabled so edited static files get a new fingerprint without restarting.
+func (r *Renderer) SetAssetCaching(enabled bool) {
+	r.assetMutex.Lock()
+	defer r.assetMutex.Unlock()
+
+	r.cacheAssets = enabled
+	r.assetCache = make(map[string]string)
+}
+
+// asset returns the fingerprinted URL of a static file (`/static/...`) or the chroma stylesheet, available to
+// templates as `asset`.
+func (r *Renderer) asset(assetPath string) (string, error) {
+	r.assetMutex.Lock()
+	defer r.assetMutex.Unlock()
+
+	if cached, ok := r.assetCache[assetPath]; ok && r.cacheAssets {
+		return cached, nil
+	}
+
+	contents, err := r.assetContents(strings.TrimPrefix(assetPath, "/"))
+	if err != nil {
+		return "", err
+	}
+
+	ret := "/" + Fingerprint(strings.TrimPrefix(assetPath, "/"), contents)
+	if r.cacheAssets {
+		r.assetCache[assetPath] = ret
+	}
+
+	return ret, nil
+}
+
+func (r *Renderer) assetContents(name string) ([]byte, error) {
+	if name == chromaStylesheet {
+		buffer := bytes.Buffer{}
+		if err := markdown.RenderCodeCSS(&buffer, r.codeStyle, r.darkCodeStyle); err != nil {
+			return nil, fmt.Errorf("error rendering CSS: %w", err)
+		}
+
+		return buffer.Bytes(), nil
+	}
+
+	if r.staticFiles == nil || !strings.HasPrefix(name, "static/") {
+		return nil, fmt.Errorf("%w: unknown asset %q", ErrNotFound, name)
+	}
+
+	contents, err := fs.ReadFile(r.staticFiles, strings.TrimPrefix(name, "static/"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading asset %q: %w", name, err)
+	}
+
+	return contents, nil
+}
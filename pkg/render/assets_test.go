@@ -0,0 +1,53 @@
+package render_test
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/anexia-it/go.anx.io/pkg/render"
+)
+
+func TestAssetCaching(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		label   string
+		caching bool
+	}{
+		{"cached", true},
+		{"not cached", false},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			renderer := newRenderer(t, examplePackage(exampleFiles))
+
+			staticFiles := fstest.MapFS{
+				"style.css": &fstest.MapFile{Data: []byte("body { color: red; }")},
+				"code.js":   &fstest.MapFile{Data: []byte("")},
+				"search.js": &fstest.MapFile{Data: []byte("")},
+			}
+
+			renderer.SetStaticFiles(staticFiles)
+			renderer.SetAssetCaching(testCase.caching)
+
+			for i, style := range []string{"body { color: red; }", "body { color: blue; }"} {
+				staticFiles["style.css"].Data = []byte(style)
+
+				buffer := bytes.Buffer{}
+				if err := renderer.RenderFile(nil, "index.html", &buffer); err != nil {
+					t.Fatalf("error rendering index.html: %v", err)
+				}
+
+				current := bytes.Contains(buffer.Bytes(), []byte(render.Fingerprint("static/style.css", []byte(style))))
+				if expected := i == 0 || !testCase.caching; current != expected {
+					t.Errorf("expected fingerprint of the current contents to be used %v after changing it %v times, got %v", expected, i, current)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/anexia-it/go.anx.io/pkg/badge"
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+const (
+	badgeFile  = "badge.svg"
+	badgeColor = "#007ec6"
+)
+
+// renderBadge writes a badge showing the import path and latest version of the major version. The badge of the
+// package without major version in the path shows the highest major version.
+func (r *Renderer) renderBadge(pkg *types.Package, majorVersion string, writer io.Writer) error {
+	if highestMajor, ok := highestMajorVersion(pkg); ok && majorVersion == "" {
+		majorVersion = highestMajor
+	}
+
+	if len(pkg.FileReader.Versions(majorVersion)) == 0 {
+		return fmt.Errorf("%w: no versions of major version %q of package %q", ErrNotFound, majorVersion, pkg.TargetName)
+	}
+
+	version, err := latestVersion(pkg, majorVersion)
+	if err != nil {
+		return err
+	}
+
+	return badge.Render(writer, path.Join("go.anx.io", pkg.TargetName, majorVersion), version, badgeColor) //nolint:wrapcheck // already wrapped
+}
@@ -0,0 +1,66 @@
+package render_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/anexia-it/go.anx.io/pkg/render"
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+func TestRenderBadge(t *testing.T) {
+	t.Parallel()
+
+	singleMajor := examplePackage(exampleFiles)
+
+	multipleMajors := packageWithVersions("multiple", map[string][]string{"": {"v1.1.0", "v1.0.0"}, "v2": {"v2.0.0"}})
+
+	v2Only := packageWithVersions("v2only", map[string][]string{"v2": {"v2.0.0"}})
+	unreleased := packageWithVersions("unreleased", map[string][]string{"": nil})
+
+	renderer := newRenderer(t, singleMajor, multipleMajors, v2Only, unreleased)
+
+	testCases := []struct {
+		label      string
+		pkg        *types.Package
+		filePath   string
+		importPath string
+		version    string
+		notFound   bool
+	}{
+		{"single major version", singleMajor, "badge.svg", "go.anx.io/example", "v1.1.0", false},
+		{"highest major version", multipleMajors, "badge.svg", "go.anx.io/multiple/v2", "v2.0.0", false},
+		{"explicit major version", multipleMajors, "v2/badge.svg", "go.anx.io/multiple/v2", "v2.0.0", false},
+		{"only higher major versions", v2Only, "badge.svg", "go.anx.io/v2only/v2", "v2.0.0", false},
+		{"unknown major version", v2Only, "v3/badge.svg", "", "", true},
+		{"without versions", unreleased, "badge.svg", "", "", true},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			buffer := bytes.Buffer{}
+			err := renderer.RenderFile(testCase.pkg, testCase.filePath, &buffer)
+
+			if testCase.notFound {
+				if !render.IsNotFound(err) {
+					t.Errorf("expected not found error, got %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("error rendering badge: %v", err)
+			}
+
+			for _, expected := range []string{testCase.importPath + "<", testCase.version + "<"} {
+				if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+					t.Errorf("expected %q in badge:\n%v", expected, buffer.String())
+				}
+			}
+		})
+	}
+}
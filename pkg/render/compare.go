@@ -0,0 +1,126 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+// comparePathPrefix is the directory compare pages are rendered into, as `compare/<from>...<to>.html`.
+const comparePathPrefix = "compare/"
+
+type compareTemplateData struct {
+	From string
+	To   string
+
+	types.Comparison
+}
+
+// parseComparePath extracts the versions to compare from the path of a compare page.
+func parseComparePath(filePath string) (string, string, bool) {
+	if !strings.HasPrefix(filePath, comparePathPrefix) || !strings.HasSuffix(filePath, ".html") {
+		return "", "", false
+	}
+
+	versions := strings.TrimSuffix(strings.TrimPrefix(filePath, comparePathPrefix), ".html")
+
+	from, to, ok := strings.Cut(versions, "...")
+	if !ok || from == "" || to == "" {
+		return "", "", false
+	}
+
+	return from, to, true
+}
+
+func comparePath(from, to string) string {
+	return fmt.Sprintf("%v%v...%v.html", comparePathPrefix, from, to)
+}
+
+// isDocumentationFile decides if we show the diff of a file on compare pages.
+func isDocumentationFile(filePath string) bool {
+	switch strings.ToLower(path.Ext(filePath)) {
+	case ".md", ".markdown", ".rst", ".txt", ".adoc":
+		return true
+	}
+
+	return false
+}
+
+func (r *Renderer) renderComparison(pkg *types.Package, majorVersion, from, to string, writer io.Writer) error {
+	comparison, err := pkg.FileReader.Compare(from, to, isDocumentationFile)
+	if err != nil {
+		return fmt.Errorf("error comparing versions %q and %q: %w", from, to, err)
+	}
+
+	diffs := strings.Builder{}
+
+	for _, file := range comparison.Files {
+		if file.Patch == "" {
+			continue
+		}
+
+		diffs.WriteString(fmt.Sprintf("### `%v`\n\n%v\n\n", file.Path, fencedCode("diff", file.Patch)))
+	}
+
+	data := packageTemplateData{
+		layoutTemplateData: layoutTemplateData{
+			Title:           fmt.Sprintf("Changes from %v to %v", from, to),
+			MarkdownContent: diffs.String(),
+			CurrentFile:     comparePath(from, to),
+			CanonicalPath:   canonicalPackagePath(pkg, majorVersion, comparePath(from, to)),
+			markdownOptions: nil,
+		},
+		Package:          pkg,
+		CurrentVersion:   to,
+		MajorVersion:     majorVersion,
+		Release:          nil,
+		Releases:         nil,
+		IsReleaseHistory: false,
+		Comparison: &compareTemplateData{
+			From:       from,
+			To:         to,
+			Comparison: comparison,
+		},
+	}
+
+	return r.executeTemplate(writer, pkg, "package.tmpl", data)
+}
+
+// releasedVersions returns the tagged versions of the given major version, newest first.
+func releasedVersions(pkg *types.Package, major string) ([]string, error) {
+	ret := make([]string, 0)
+
+	for _, version := range pkg.FileReader.Versions(major) {
+		info, err := pkg.FileReader.VersionInfo(version)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving info for version %q: %w", version, err)
+		}
+
+		if !info.IsBranch {
+			ret = append(ret, version)
+		}
+	}
+
+	return ret, nil
+}
+
+// latestVersion returns the latest release of the major version, or its latest version if it has no releases yet.
+func latestVersion(pkg *types.Package, major string) (string, error) {
+	releases, err := releasedVersions(pkg, major)
+	if err != nil {
+		return "", err
+	}
+
+	if len(releases) > 0 {
+		return releases[0], nil
+	}
+
+	if versions := pkg.FileReader.Versions(major); len(versions) > 0 {
+		return versions[0], nil
+	}
+
+	return "", nil
+}
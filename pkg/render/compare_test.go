@@ -0,0 +1,127 @@
+package render_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anexia-it/go.anx.io/pkg/render"
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+func TestComparePath(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		path string
+		from string
+		to   string
+		ok   bool
+	}{
+		{"compare/v1.0.0...v1.1.0.html", "v1.0.0", "v1.1.0", true},
+		{"compare/v2.0.0-rc.1...v2.0.0.html", "v2.0.0-rc.1", "v2.0.0", true},
+		{"compare/v1.0.0..v1.1.0.html", "", "", false},
+		{"compare/...v1.1.0.html", "", "", false},
+		{"compare/v1.0.0....html", "", "", false},
+		{"compare/v1.0.0...v1.1.0", "", "", false},
+		{"v1.0.0...v1.1.0.html", "", "", false},
+		{"README.md", "", "", false},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.path, func(t *testing.T) {
+			t.Parallel()
+
+			from, to, ok := render.ParseComparePath(testCase.path)
+			if from != testCase.from || to != testCase.to || ok != testCase.ok {
+				t.Fatalf("%q %q %v (actual) did not match %q %q %v (expected)", from, to, ok, testCase.from, testCase.to, testCase.ok)
+			}
+
+			if ok {
+				if path := render.ComparePath(from, to); path != testCase.path {
+					t.Errorf("%q (actual) did not match %q (expected)", path, testCase.path)
+				}
+			}
+		})
+	}
+}
+
+// comparingFileReader returns the given comparison, recording the files a patch was requested for.
+type comparingFileReader struct {
+	memoryFileReader
+
+	comparison types.Comparison
+	patched    *[]string
+}
+
+func (r comparingFileReader) Compare(_, _ string, withPatch func(path string) bool) (types.Comparison, error) {
+	for _, file := range r.comparison.Files {
+		if withPatch(file.Path) {
+			*r.patched = append(*r.patched, file.Path)
+		}
+	}
+
+	return r.comparison, nil
+}
+
+func TestRenderComparison(t *testing.T) {
+	t.Parallel()
+
+	patched := make([]string, 0)
+
+	pkg := examplePackage(exampleFiles)
+	pkg.FileReader = comparingFileReader{
+		memoryFileReader: memoryFileReader{versions: map[string][]string{"": {"v1.1.0", "v1.0.0"}}, files: exampleFiles},
+		comparison: types.Comparison{
+			Commits: []types.CommitInfo{
+				{
+					Hash:    "0123456789abcdef0123456789abcdef01234567",
+					Summary: "Add greeting",
+					Author:  "Jane Doe",
+					Date:    time.Date(2024, 1, 9, 12, 0, 0, 0, time.UTC),
+				},
+			},
+			Files: []types.FileChange{
+				{Path: "README.md", OldPath: "", Action: "modified", Patch: "@@ -1 +1 @@\n-# Old\n+# Example\n"},
+				{Path: "example.go", OldPath: "", Action: "added", Patch: ""},
+				{Path: "greeting.go", OldPath: "hello.go", Action: "renamed", Patch: ""},
+			},
+		},
+		patched: &patched,
+	}
+
+	renderer := newRenderer(t, pkg)
+
+	buffer := bytes.Buffer{}
+	if err := renderer.RenderFile(pkg, "compare/v1.0.0...v1.1.0.html", &buffer); err != nil {
+		t.Fatalf("error rendering comparison: %v", err)
+	}
+
+	for _, expected := range []string{
+		"Changes from v1.0.0 to v1.1.0",
+		`<code title="0123456789abcdef0123456789abcdef01234567">01234567</code>`,
+		"Add greeting",
+		`<time datetime="2024-01-09T12:00:00Z">2024-01-09</time>`,
+		`<code>example.go</code> (added)`,
+		`<code>hello.go</code> &rarr; <code>greeting.go</code> (renamed)`,
+		"<code>README.md</code></h3>",
+		"+# Example",
+		`<link rel="canonical" href="/example/compare/v1.0.0...v1.1.0.html"`,
+	} {
+		if !strings.Contains(buffer.String(), expected) {
+			t.Errorf("expected %q in comparison:\n%v", expected, buffer.String())
+		}
+	}
+
+	if strings.Join(patched, ",") != "README.md" {
+		t.Errorf("expected a patch for README.md only, got %v", patched)
+	}
+
+	err := renderer.RenderFile(pkg, "compare/v1.0.0...v1.1.0.html@v1.1.0", &bytes.Buffer{})
+	if !errors.Is(err, render.ErrNotFound) {
+		t.Errorf("expected not found for versioned comparison, got %v", err)
+	}
+}
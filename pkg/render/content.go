@@ -1,11 +1,12 @@
 package render
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
-	"os"
 	"path"
+	"strings"
 
 	"github.com/anexia-it/go.anx.io/pkg/markdown"
 	"github.com/anexia-it/go.anx.io/pkg/types"
@@ -19,16 +20,54 @@ type mainTemplateData struct {
 func (r *Renderer) renderContentFile(filePath string, writer io.Writer) error {
 	if filePath == "" || filePath == "index.html" {
 		filePath = "index.md"
-	} else if filePath == "chroma/style.css" {
-		if err := markdown.RenderCodeCSS(writer); err != nil {
+	} else if original, ok := StripFingerprint(filePath); ok && original == chromaStylesheet {
+		fingerprinted, err := r.asset("/" + chromaStylesheet)
+		if err != nil {
+			return err
+		} else if fingerprinted != "/"+filePath {
+			return fmt.Errorf("%w: outdated fingerprint of %q", ErrNotFound, filePath)
+		}
+
+		filePath = chromaStylesheet
+	}
+
+	if filePath == chromaStylesheet {
+		if err := markdown.RenderCodeCSS(writer, r.codeStyle, r.darkCodeStyle); err != nil {
 			return fmt.Errorf("error rendering CSS: %w", err)
 		}
 
 		return nil
+	} else if filePath == sitemapFile {
+		return r.renderSitemap(writer)
+	} else if filePath == robotsFile {
+		return r.renderRobotsTxt(writer)
+	} else if filePath == notFoundFile {
+		return r.RenderNotFound(nil, "", writer)
+	} else if filePath == releaseFeedFile {
+		return r.renderReleaseFeed(nil, writer)
+	} else if strings.HasPrefix(filePath, apiPathPrefix) {
+		return r.renderAPIFile(filePath, writer)
+	} else if filePath == searchIndexFile {
+		return r.renderSearchIndex(writer)
+	} else if filePath == searchPageFile {
+		data := mainTemplateData{
+			layoutTemplateData: layoutTemplateData{
+				Title:           "Search",
+				CurrentFile:     filePath,
+				MarkdownContent: "",
+				CanonicalPath:   "/" + filePath,
+				markdownOptions: nil,
+			},
+			Packages: r.packages,
+		}
+
+		return r.executeTemplate(writer, nil, "search.tmpl", data)
 	}
 
-	content, err := os.ReadFile(path.Join("content", filePath))
-	if err != nil {
+	content, err := fs.ReadFile(r.content, filePath)
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+		return fmt.Errorf("%w: content file %q", ErrNotFound, filePath)
+	} else if err != nil {
 		return fmt.Errorf("error reading source file '%v': %w", filePath, err)
 	}
 
@@ -42,19 +81,27 @@ func (r *Renderer) renderContentFile(filePath string, writer io.Writer) error {
 			Title:           "",
 			CurrentFile:     filePath,
 			MarkdownContent: markdown,
+			CanonicalPath:   canonicalContentPath(filePath),
+			markdownOptions: nil,
 		},
 		Packages: r.packages,
 	}
 
-	return r.executeTemplate(writer, "main.tmpl", data)
+	return r.executeTemplate(writer, nil, "main.tmpl", data)
 }
 
 func (r *Renderer) filesForContent() ([]string, error) {
-	ret := []string{"chroma/style.css"}
+	chromaFingerprinted, err := r.asset("/" + chromaStylesheet)
+	if err != nil {
+		return nil, err
+	}
 
-	contentFiles, err := fs.Glob(os.DirFS(r.contentPath), "*")
+	ret := []string{chromaStylesheet, strings.TrimPrefix(chromaFingerprinted, "/"), searchIndexFile, searchPageFile, sitemapFile, robotsFile, releaseFeedFile, notFoundFile}
+	ret = append(ret, r.filesForAPI()...)
+
+	contentFiles, err := fs.Glob(r.content, "*")
 	if err != nil {
-		return nil, fmt.Errorf("error listing files in content directors %q: %w", r.contentPath, err)
+		return nil, fmt.Errorf("error listing content files: %w", err)
 	}
 
 	for _, f := range contentFiles {
@@ -68,13 +115,32 @@ func (r *Renderer) filesForContent() ([]string, error) {
 	return ret, nil
 }
 
+func canonicalContentPath(filePath string) string {
+	if filePath == "index.md" {
+		return "/"
+	}
+
+	return "/" + filePath
+}
+
 func markdownContent(content string, filePath string) (string, error) {
 	switch path.Ext(filePath) {
 	case ".md":
 		return content, nil
 	case ".go":
-		return fmt.Sprintf("# `%v`\n\n```go\n%v\n```", filePath, content), nil
+		return fmt.Sprintf("# `%v`\n\n%v", filePath, fencedCode("go", content)), nil
 	default:
-		return "", fmt.Errorf("%w: unknown file extension", os.ErrInvalid)
+		return "", fmt.Errorf("%w: unknown file extension of %q", ErrNotFound, filePath)
 	}
 }
+
+// fencedCode wraps the code in a markdown fenced code block, using a fence longer than every
+// sequence of backticks in the code.
+func fencedCode(language, code string) string {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+
+	return fmt.Sprintf("%v%v\n%v\n%v", fence, language, strings.TrimSuffix(code, "\n"), fence)
+}
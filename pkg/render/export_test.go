@@ -0,0 +1,7 @@
+package render
+
+// unexported functions tested in package render_test
+var (
+	ParseComparePath = parseComparePath
+	ComparePath      = comparePath
+)
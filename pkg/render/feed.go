@@ -0,0 +1,186 @@
+package render
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/anexia-it/go.anx.io/pkg/markdown"
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+// releaseFeedFile is the file name of the Atom feeds of new releases, both site-wide and per package.
+const releaseFeedFile = "releases.atom"
+
+// maxSiteFeedEntries limits the number of releases in the site-wide feed.
+const maxSiteFeedEntries = 100
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID      string       `xml:"id"`
+	Title   string       `xml:"title"`
+	Updated string       `xml:"updated"`
+	Link    atomLink     `xml:"link"`
+	Content *atomContent `xml:"content,omitempty"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type feedRelease struct {
+	pkg     *types.Package
+	major   string
+	release *releaseTemplateData
+}
+
+// packageReleases collects the releases of every major version of the package.
+func (r *Renderer) packageReleases(pkg *types.Package) ([]feedRelease, error) {
+	ret := make([]feedRelease, 0)
+
+	for _, major := range pkg.FileReader.MajorVersions() {
+		versions, err := releasedVersions(pkg, major)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, version := range versions {
+			release, err := r.releaseNotes(pkg, version)
+			if err != nil {
+				return nil, err
+			}
+
+			ret = append(ret, feedRelease{pkg, major, release})
+		}
+	}
+
+	return ret, nil
+}
+
+// renderReleaseFeed writes an Atom feed of the releases of the given package, or of all packages if pkg is nil.
+func (r *Renderer) renderReleaseFeed(pkg *types.Package, writer io.Writer) error {
+	packages := r.packages
+	title := "go.anx.io - releases"
+	feedPath := "/" + releaseFeedFile
+	pagePath := "/"
+
+	if pkg != nil {
+		packages = []*types.Package{pkg}
+		title = fmt.Sprintf("go.anx.io/%v - releases", pkg.TargetName)
+		feedPath = "/" + path.Join(pkg.TargetName, releaseFeedFile)
+		pagePath = "/" + pkg.TargetName + "/"
+	}
+
+	releases := make([]feedRelease, 0)
+
+	for _, p := range packages {
+		packageReleases, err := r.packageReleases(p)
+		if err != nil {
+			return err
+		}
+
+		releases = append(releases, packageReleases...)
+	}
+
+	sort.SliceStable(releases, func(a, b int) bool {
+		return releases[a].release.Date.After(releases[b].release.Date)
+	})
+
+	if pkg == nil && len(releases) > maxSiteFeedEntries {
+		releases = releases[:maxSiteFeedEntries]
+	}
+
+	// feeds without releases were last updated when the site was built, which is reproducible, too
+	updated, err := r.currentBuildTime()
+	if err != nil {
+		return err
+	}
+
+	if len(releases) > 0 {
+		updated = releases[0].release.Date
+	}
+
+	feed := atomFeed{
+		XMLName: xml.Name{Space: "", Local: ""},
+		ID:      r.baseURL + feedPath,
+		Title:   title,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: "Anexia"},
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: r.baseURL + feedPath},
+			{Rel: "alternate", Type: "text/html", Href: r.baseURL + pagePath},
+		},
+		Entries: make([]atomEntry, 0, len(releases)),
+	}
+
+	for _, release := range releases {
+		entry, err := r.feedEntry(release)
+		if err != nil {
+			return err
+		}
+
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return fmt.Errorf("error writing feed: %w", err)
+	}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(feed); err != nil {
+		return fmt.Errorf("error encoding feed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Renderer) feedEntry(release feedRelease) (atomEntry, error) {
+	url := r.baseURL + "/" + path.Join(release.pkg.TargetName, release.major, "README.md@"+release.release.Version)
+
+	entry := atomEntry{
+		ID:      url,
+		Title:   fmt.Sprintf("%v %v", path.Join("go.anx.io", release.pkg.TargetName, release.major), release.release.Version),
+		Updated: release.release.Date.UTC().Format(time.RFC3339),
+		Link:    atomLink{Rel: "alternate", Type: "text/html", Href: url},
+		Content: nil,
+	}
+
+	// we prefer the tag message, using the changelog section for lightweight tags
+	if release.release.TagMessage != "" {
+		entry.Content = &atomContent{Type: "text", Body: release.release.TagMessage}
+	} else if release.release.Changes != "" {
+		changes, err := markdown.RenderMarkdown(release.release.Changes)
+		if err != nil {
+			return entry, fmt.Errorf("error rendering changes of version %q: %w", release.release.Version, err)
+		}
+
+		entry.Content = &atomContent{Type: "html", Body: string(changes)}
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,47 @@
+package render_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+func TestReleaseFeedUpdated(t *testing.T) {
+	t.Parallel()
+
+	unreleased := packageWithVersions("unreleased", map[string][]string{"": nil})
+
+	testCases := []struct {
+		label     string
+		pkg       *types.Package
+		buildTime time.Time
+		updated   string
+	}{
+		{"newest release", examplePackage(exampleFiles), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), "<updated>2024-01-10T12:00:00Z</updated>"},
+		{"without releases", unreleased, time.Time{}, "<updated>2024-01-10T12:00:00Z</updated>"},
+		{"without releases and build time", unreleased, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), "<updated>2024-02-01T00:00:00Z</updated>"},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			renderer := newRenderer(t, examplePackage(exampleFiles), unreleased)
+			if !testCase.buildTime.IsZero() {
+				renderer.SetBuildTime(testCase.buildTime)
+			}
+
+			buffer := bytes.Buffer{}
+			if err := renderer.RenderFile(testCase.pkg, "releases.atom", &buffer); err != nil {
+				t.Fatalf("error rendering feed: %v", err)
+			}
+
+			if !bytes.Contains(buffer.Bytes(), []byte(testCase.updated)) {
+				t.Errorf("expected %v in feed:\n%v", testCase.updated, buffer.String())
+			}
+		})
+	}
+}
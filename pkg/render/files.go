@@ -3,15 +3,24 @@
 package render
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"log"
 	"os"
 	"path"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/anexia-it/go.anx.io/pkg/compression"
 	"github.com/anexia-it/go.anx.io/pkg/types"
 )
 
+// GenerateFiles renders every file of the site into destPath. Files are rendered in parallel, errors
+// rendering single files don't stop rendering the others but are all returned together.
 func (r *Renderer) GenerateFiles(destPath string) error {
 	type pkgFiles struct {
 		pkg   *types.Package
@@ -21,9 +30,14 @@ func (r *Renderer) GenerateFiles(destPath string) error {
 	allFiles := make([]pkgFiles, 0, len(r.packages)+1)
 
 	for _, pkg := range r.packages {
+		files, err := r.filesForPackage(pkg)
+		if err != nil {
+			return err
+		}
+
 		allFiles = append(allFiles, pkgFiles{
 			pkg:   pkg,
-			files: r.filesForPackage(pkg),
+			files: files,
 		})
 	}
 
@@ -36,7 +50,10 @@ func (r *Renderer) GenerateFiles(destPath string) error {
 		return err
 	}
 
-	for _, pf := range allFiles {
+	jobs := make([]generateJob, 0)
+	directories := make(map[string]bool)
+
+	for pkgIndex, pf := range allFiles {
 		pkg := pf.pkg
 
 		for _, file := range pf.files {
@@ -54,28 +71,78 @@ func (r *Renderer) GenerateFiles(destPath string) error {
 				destinationPath = path.Join(destinationPath, "index.html")
 			}
 
-			destinationDirectory := path.Dir(destinationPath)
-			if err := os.MkdirAll(destinationDirectory, os.ModeDir|0755); err != nil {
-				return fmt.Errorf("error creating directory %q: %w", destinationDirectory, err)
-			}
+			directories[path.Dir(destinationPath)] = true
+			jobs = append(jobs, generateJob{pkgIndex: pkgIndex, pkg: pkg, file: file, dest: destinationPath})
+		}
+	}
+
+	// creating the directories up front saves every worker from checking them again
+	for directory := range directories {
+		if err := os.MkdirAll(directory, os.ModeDir|0755); err != nil {
+			return fmt.Errorf("error creating directory %q: %w", directory, err)
+		}
+	}
+
+	errs := make([]error, len(jobs))
+	durations := make([]time.Duration, len(allFiles))
+	durationMutex := sync.Mutex{}
+
+	jobIndexes := make(chan int)
+	workers := sync.WaitGroup{}
+
+	for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+		workers.Add(1)
+
+		go func() {
+			defer workers.Done()
+
+			for jobIndex := range jobIndexes {
+				job := jobs[jobIndex]
+				start := time.Now()
 
-			if err := r.generateFile(pkg, destinationPath, file); err != nil {
-				return err
+				errs[jobIndex] = r.generateFile(job.pkg, job.dest, job.file)
+
+				durationMutex.Lock()
+				durations[job.pkgIndex] += time.Since(start)
+				durationMutex.Unlock()
 			}
+		}()
+	}
+
+	for jobIndex := range jobs {
+		jobIndexes <- jobIndex
+	}
+
+	close(jobIndexes)
+	workers.Wait()
+
+	for pkgIndex, pf := range allFiles {
+		name := "content"
+		if pf.pkg != nil {
+			name = "package " + pf.pkg.TargetName
 		}
+
+		log.Printf("Rendered %v files of %v, taking %v of render time", len(pf.files), name, durations[pkgIndex].Round(time.Millisecond))
 	}
 
-	return nil
+	//nolint:wrapcheck // every error already has the context of its file
+	return errors.Join(errs...)
+}
+
+// generateJob is a single file to be rendered by GenerateFiles.
+type generateJob struct {
+	// pkgIndex is the index of pkg in the files to generate, for summing up the render time per package.
+	pkgIndex int
+
+	pkg  *types.Package
+	file string
+	dest string
 }
 
 func (r *Renderer) generateFile(pkg *types.Package, dest, file string) error {
-	//nolint:nosnakecase // O_WRONLY, O_CREATE and O_TRUNC are defined by the os package (and underlying POSIX spec).
-	destinationStream, err := os.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
-	if err != nil {
-		return fmt.Errorf("error opening file %q (create|truncate|write): %w", dest, err)
-	}
+	buffer := bytes.Buffer{}
 
-	if err := r.RenderFile(pkg, file, destinationStream); err != nil {
+	if err := r.RenderFile(pkg, file, &buffer); err != nil {
 		pkgName := "<nil>"
 		if pkg != nil {
 			pkgName = pkg.TargetName
@@ -84,8 +151,13 @@ func (r *Renderer) generateFile(pkg *types.Package, dest, file string) error {
 		return fmt.Errorf("error rendering file %q for package %q: %w", file, pkgName, err)
 	}
 
-	if err := destinationStream.Close(); err != nil {
-		return fmt.Errorf("error closing file %q after writing to it: %w", dest, err)
+	if err := os.WriteFile(dest, buffer.Bytes(), 0644); err != nil {
+		return fmt.Errorf("error writing file %q: %w", dest, err)
+	}
+
+	if r.precompress {
+		//nolint:wrapcheck // error already has enough context
+		return compression.WritePrecompressed(dest, buffer.Bytes())
 	}
 
 	return nil
@@ -97,7 +169,7 @@ func (r *Renderer) generateFile(pkg *types.Package, dest, file string) error {
 // we create a directory for those files and place a `index.html` in that.
 func fileExtensionNeedsMIMEHack(fileExt string) bool {
 	// This array defines the file extensions we do this for.
-	mimeTypeHackExtensions := []string{".md"}
+	mimeTypeHackExtensions := []string{".md", ".go"}
 
 	// we need it sorted for fast check if a given extension is in this array
 	sort.Strings(mimeTypeHackExtensions)
@@ -0,0 +1,266 @@
+package render_test
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	goanxio "github.com/anexia-it/go.anx.io"
+	"github.com/anexia-it/go.anx.io/pkg/render"
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+// memoryFileReader is a types.VersionedFileReader with the same files in every version.
+type memoryFileReader struct {
+	// versions holds the versions of each major version, newest first
+	versions map[string][]string
+	files    map[string]string
+}
+
+func (r memoryFileReader) MajorVersions() []string {
+	ret := make([]string, 0, len(r.versions))
+	for major := range r.versions {
+		ret = append(ret, major)
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(ret)))
+
+	return ret
+}
+
+func (r memoryFileReader) Versions(major string) []string {
+	return r.versions[major]
+}
+
+func (r memoryFileReader) ReadFile(path, version string) (string, error) {
+	if contents, ok := r.files[path]; ok {
+		return contents, nil
+	}
+
+	return "", fmt.Errorf("%w: %q in version %q", types.ErrFileNotFound, path, version)
+}
+
+func (r memoryFileReader) Files(_ string) ([]string, error) {
+	ret := make([]string, 0, len(r.files))
+	for file := range r.files {
+		ret = append(ret, file)
+	}
+
+	sort.Strings(ret)
+
+	return ret, nil
+}
+
+func (r memoryFileReader) VersionInfo(version string) (types.VersionInfo, error) {
+	versions := make([]string, 0)
+	for _, major := range r.MajorVersions() {
+		versions = append(versions, r.versions[major]...)
+	}
+
+	for i, v := range versions {
+		if v == version {
+			date := time.Date(2024, 1, 10-i, 12, 0, 0, 0, time.UTC)
+
+			return types.VersionInfo{
+				Commit:     fmt.Sprintf("%040d", len(versions)-i),
+				Date:       date,
+				CommitDate: date,
+				TagMessage: "Release " + version,
+				IsBranch:   false,
+			}, nil
+		}
+	}
+
+	return types.VersionInfo{}, fmt.Errorf("%w: %q", types.ErrVersionNotFound, version)
+}
+
+func (r memoryFileReader) Compare(_, _ string, _ func(path string) bool) (types.Comparison, error) {
+	return types.Comparison{Commits: nil, Files: nil}, nil
+}
+
+// examplePackage returns a package with the given files in the versions v1.1.0 and v1.0.0.
+func examplePackage(files map[string]string) *types.Package {
+	return &types.Package{
+		Source:     "https://github.com/anexia/go-example.git",
+		TargetName: "example",
+		Summary:    "Example package",
+		SourceHost: "",
+		SourceURLs: types.SourceURLTemplates{Directory: "", File: "", Line: ""},
+		Versions:   nil,
+		FileReader: memoryFileReader{
+			versions: map[string][]string{"": {"v1.1.0", "v1.0.0"}},
+			files:    files,
+		},
+	}
+}
+
+// packageWithVersions returns a package with the example files in the given versions of each major version.
+func packageWithVersions(targetName string, versions map[string][]string) *types.Package {
+	pkg := examplePackage(exampleFiles)
+	pkg.TargetName = targetName
+	pkg.FileReader = memoryFileReader{versions: versions, files: exampleFiles}
+
+	return pkg
+}
+
+var exampleFiles = map[string]string{
+	"go.mod":           "module go.anx.io/example\n\ngo 1.21\n",
+	"README.md":        "# Example\n\n## Usage\n\n```go\nexample.Hello()\n```\n\n## License\n",
+	"example.go":       "package example\n\n// Hello says hello.\nfunc Hello() string {\n\treturn \"hello\"\n}\n",
+	"client/client.go": "package client\n\n// Client is a client.\ntype Client struct{}\n",
+}
+
+// newRenderer creates a renderer for the given packages with the embedded templates, content and static files.
+func newRenderer(t *testing.T, packages ...*types.Package) *render.Renderer {
+	t.Helper()
+
+	renderer, err := render.NewRenderer(goanxio.Templates(), goanxio.Content(), packages)
+	if err != nil {
+		t.Fatalf("error creating renderer: %v", err)
+	}
+
+	renderer.SetStaticFiles(goanxio.Static())
+
+	return renderer
+}
+
+// generate generates the site for the given package, returning the generated files by path.
+func generate(t *testing.T, pkg *types.Package) (map[string][]byte, error) {
+	t.Helper()
+
+	destination := t.TempDir()
+	generateErr := newRenderer(t, pkg).GenerateFiles(destination)
+
+	ret := make(map[string][]byte)
+
+	err := filepath.WalkDir(destination, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		contents, err := os.ReadFile(path)
+		ret[filepath.ToSlash(path[len(destination):])] = contents
+
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error reading generated files: %v", err)
+	}
+
+	return ret, generateErr
+}
+
+func TestGenerateFilesReproducible(t *testing.T) {
+	t.Parallel()
+
+	first, err := generate(t, examplePackage(exampleFiles))
+	if err != nil {
+		t.Fatalf("error generating files: %v", err)
+	}
+
+	second, err := generate(t, examplePackage(exampleFiles))
+	if err != nil {
+		t.Fatalf("error generating files: %v", err)
+	}
+
+	if len(first) == 0 {
+		t.Fatal("no files generated")
+	}
+
+	for file, contents := range first {
+		if other, ok := second[file]; !ok {
+			t.Errorf("file %q only generated the first time", file)
+		} else if !bytes.Equal(contents, other) {
+			t.Errorf("file %q differs between generating twice", file)
+		}
+	}
+
+	for file := range second {
+		if _, ok := first[file]; !ok {
+			t.Errorf("file %q only generated the second time", file)
+		}
+	}
+
+	if index := first["/index.html"]; !bytes.Contains(index, []byte("2024-01-10")) {
+		t.Errorf("expected the date of the newest commit as build time in index.html")
+	}
+}
+
+func TestGenerateFilesReportsAllErrors(t *testing.T) {
+	t.Parallel()
+
+	files := make(map[string]string, len(exampleFiles))
+	for name, contents := range exampleFiles {
+		if name != "README.md" {
+			files[name] = contents
+		}
+	}
+
+	generated, err := generate(t, examplePackage(files))
+	if err == nil {
+		t.Fatal("expected an error generating files without README.md")
+	}
+
+	for _, file := range []string{`"index.html"`, `"README.md@v1.0.0"`, `"README.md@v1.1.0"`} {
+		if !strings.Contains(err.Error(), file) {
+			t.Errorf("expected error for file %v, got %v", file, err)
+		}
+	}
+
+	if _, ok := generated["/example/example.go/index.html"]; !ok {
+		t.Errorf("expected files not failing to be generated")
+	}
+}
+
+func TestGenerateFilesLinkedVersions(t *testing.T) {
+	t.Parallel()
+
+	generated, err := generate(t, examplePackage(exampleFiles))
+	if err != nil {
+		t.Fatalf("error generating files: %v", err)
+	}
+
+	// source files are only generated for the latest version, older versions link to pkg.go.dev
+	readme := generated["/example/README.md@v1.1.0/index.html"]
+	if !bytes.Contains(readme, []byte(`href="/example/example.go@v1.1.0#`)) {
+		t.Errorf("expected the README of v1.1.0 to link to example.go of v1.1.0")
+	}
+
+	readme = generated["/example/README.md@v1.0.0/index.html"]
+	if !bytes.Contains(readme, []byte(`href="https://pkg.go.dev/go.anx.io/example@v1.0.0#`)) {
+		t.Errorf("expected the README of v1.0.0 to link to pkg.go.dev for v1.0.0")
+	}
+
+	if _, ok := generated["/example/example.go@v1.0.0/index.html"]; ok {
+		t.Errorf("expected no source page for v1.0.0")
+	}
+
+	linkRegex := regexp.MustCompile(`href="(/example/[^"#?]*@[^"#?]*)`)
+
+	for file, contents := range generated {
+		if !strings.HasSuffix(file, ".html") {
+			continue
+		}
+
+		for _, match := range linkRegex.FindAllSubmatch(contents, -1) {
+			target := html.UnescapeString(string(match[1]))
+
+			if _, ok := generated[target]; ok {
+				continue
+			}
+
+			if _, ok := generated[path.Join(target, "index.html")]; !ok {
+				t.Errorf("link to %q in %q is not generated", target, file)
+			}
+		}
+	}
+}
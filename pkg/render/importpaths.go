@@ -0,0 +1,138 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/anexia-it/go.anx.io/pkg/symbols"
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+type importPathTemplateData struct {
+	packageTemplateData
+
+	// ImportPath is the import path of the Go package in Directory of the module.
+	ImportPath string
+	Directory  string
+
+	// GoImportOnly omits the go-source meta tag, which needs the commit of the version.
+	GoImportOnly bool
+}
+
+// packageVersion identifies a version of a package, used as key for caches.
+type packageVersion struct {
+	pkg     *types.Package
+	version string
+}
+
+// packageDirs returns the directories with importable Go packages in the given version of the package, caching
+// them since they are needed for every request of a path not being the README.
+func (r *Renderer) packageDirs(pkg *types.Package, version string) ([]string, error) {
+	r.packageDirsMutex.Lock()
+	defer r.packageDirsMutex.Unlock()
+
+	key := packageVersion{pkg: pkg, version: version}
+	if cached, ok := r.packageDirsCache[key]; ok {
+		return cached, nil
+	}
+
+	dirs, err := listPackageDirs(pkg, version)
+	if err != nil {
+		return nil, err
+	}
+
+	r.packageDirsCache[key] = dirs
+
+	return dirs, nil
+}
+
+// listPackageDirs lists the directories with importable Go packages in the given version of the package,
+// excluding the module root and directories the go tool ignores or belonging to nested modules.
+func listPackageDirs(pkg *types.Package, version string) ([]string, error) {
+	files, err := pkg.FileReader.Files(version)
+	if err != nil {
+		return nil, fmt.Errorf("error listing files of version %q of package %q: %w", version, pkg.TargetName, err)
+	}
+
+	dirs := make(map[string]bool)
+
+	for _, file := range symbols.SourceFiles(files) {
+		if dir := path.Dir(file); dir != "." && !strings.HasSuffix(file, "_test.go") {
+			dirs[dir] = true
+		}
+	}
+
+	ret := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		ret = append(ret, dir)
+	}
+
+	sort.Strings(ret)
+
+	return ret, nil
+}
+
+// importPathDir returns the package directory a path points to, if it does.
+func (r *Renderer) importPathDir(pkg *types.Package, version, filePath string) (string, bool, error) {
+	dir := strings.TrimSuffix(strings.TrimSuffix(filePath, "index.html"), "/")
+	if dir == "" {
+		return "", false, nil
+	}
+
+	dirs, err := r.packageDirs(pkg, version)
+	if err != nil {
+		return "", false, err
+	}
+
+	i := sort.SearchStrings(dirs, dir)
+
+	return dir, i < len(dirs) && dirs[i] == dir, nil
+}
+
+// renderImportPath renders a minimal page with the go-import and go-source meta tags for a package of the module,
+// for tools that don't walk up the import path to find the module.
+func (r *Renderer) renderImportPath(pkg *types.Package, majorVersion, version, dir string, goImportOnly bool, writer io.Writer) error {
+	data := importPathTemplateData{
+		packageTemplateData: packageTemplateData{
+			layoutTemplateData: layoutTemplateData{
+				Title:           dir,
+				CurrentFile:     dir,
+				MarkdownContent: "",
+				CanonicalPath:   canonicalPackagePath(pkg, majorVersion, ""),
+				markdownOptions: nil,
+			},
+			Package:          pkg,
+			CurrentVersion:   version,
+			MajorVersion:     majorVersion,
+			Release:          nil,
+			Releases:         nil,
+			IsReleaseHistory: false,
+			Comparison:       nil,
+		},
+		ImportPath:   path.Join("go.anx.io", pkg.TargetName, majorVersion, dir),
+		Directory:    dir,
+		GoImportOnly: goImportOnly,
+	}
+
+	return r.executeTemplate(writer, pkg, "importpath.tmpl", data)
+}
+
+// RenderGoImport renders the page with the go-import meta tag for any path in the package, without checking if
+// the path is an actual package of the module. This is all the go tool needs for `go get`, so ?go-get=1 requests
+// can be answered without reading the repository.
+func (r *Renderer) RenderGoImport(pkg *types.Package, filePath string, writer io.Writer) error {
+	majorVersion, filePath, moduleVersions := splitMajorVersion(pkg, filePath)
+	if len(moduleVersions) == 0 {
+		return fmt.Errorf("%w: no versions of major version %q of package %q: %w", ErrNotFound, majorVersion, pkg.TargetName, fs.ErrNotExist)
+	}
+
+	// versions are not part of import paths, but tools might request them anyway
+	dir := strings.TrimSuffix(strings.SplitN(filePath, "@", 2)[0], "index.html")
+	dir = strings.Trim(path.Clean("/"+dir), "/")
+
+	return r.renderImportPath(pkg, majorVersion, moduleVersions[0], dir, true, writer)
+}
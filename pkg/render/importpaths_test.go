@@ -0,0 +1,116 @@
+package render_test
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/anexia-it/go.anx.io/pkg/render"
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+func TestRenderGoImport(t *testing.T) {
+	t.Parallel()
+
+	withoutVersions := packageWithVersions("unreleased", map[string][]string{"": nil})
+	renderer := newRenderer(t, examplePackage(exampleFiles), withoutVersions)
+
+	testCases := []struct {
+		label    string
+		pkg      *types.Package
+		filePath string
+		contains string
+		notFound bool
+	}{
+		{"module", examplePackage(exampleFiles), "", `<meta name="go-import" content="go.anx.io/example git `, false},
+		{"package", examplePackage(exampleFiles), "client/foo", "go.anx.io/example/client/foo", false},
+		{"versioned", examplePackage(exampleFiles), "client@v1.0.0", "go.anx.io/example/client<", false},
+		{"unknown major version", examplePackage(exampleFiles), "v2/client", "go.anx.io/example/v2/client", false},
+		{"no versions", withoutVersions, "client", "", true},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			buffer := bytes.Buffer{}
+			err := renderer.RenderGoImport(testCase.pkg, testCase.filePath, &buffer)
+
+			if testCase.notFound {
+				if !render.IsNotFound(err) || !errors.Is(err, fs.ErrNotExist) {
+					t.Errorf("expected not found error, got %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("error rendering go-import: %v", err)
+			}
+
+			if !strings.Contains(buffer.String(), testCase.contains) {
+				t.Errorf("expected %q in rendered page:\n%v", testCase.contains, buffer.String())
+			}
+
+			if strings.Contains(buffer.String(), "go-source") {
+				t.Errorf("expected no go-source meta tag in rendered page:\n%v", buffer.String())
+			}
+		})
+	}
+}
+
+// countingFileReader counts the calls of Files.
+type countingFileReader struct {
+	memoryFileReader
+
+	filesCalls *atomic.Int32
+}
+
+func (r countingFileReader) Files(version string) ([]string, error) {
+	r.filesCalls.Add(1)
+	return r.memoryFileReader.Files(version)
+}
+
+func TestPackageDirsCached(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		label     string
+		filePaths []string
+		calls     int32
+	}{
+		{"single version", []string{"client/", "client/index.html", "client"}, 1},
+		{"two versions", []string{"client/", "client@v1.0.0", "client", "client@v1.0.0"}, 2},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			reader := countingFileReader{
+				memoryFileReader: memoryFileReader{versions: map[string][]string{"": {"v1.1.0", "v1.0.0"}}, files: exampleFiles},
+				filesCalls:       &atomic.Int32{},
+			}
+
+			pkg := examplePackage(exampleFiles)
+			pkg.FileReader = reader
+
+			renderer := newRenderer(t, pkg)
+
+			for _, filePath := range testCase.filePaths {
+				if err := renderer.RenderFile(pkg, filePath, &bytes.Buffer{}); err != nil {
+					t.Fatalf("error rendering %q: %v", filePath, err)
+				}
+			}
+
+			if calls := reader.filesCalls.Load(); calls != testCase.calls {
+				t.Errorf("expected files to be listed %v times, got %v times", testCase.calls, calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+// ErrNotFound is returned for requested pages that don't exist, use IsNotFound to also catch the errors of the
+// VersionedFileReader about missing files and versions.
+var ErrNotFound = errors.New("not found")
+
+const notFoundFile = "404.html"
+
+// IsNotFound returns true if rendering failed because the requested page, file or version does not exist, false
+// for internal errors.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, types.ErrFileNotFound) || errors.Is(err, types.ErrVersionNotFound)
+}
+
+type notFoundSuggestion struct {
+	Prefix   string
+	LinkText string
+	Link     string
+	Suffix   string
+}
+
+type notFoundTemplateData struct {
+	layoutTemplateData
+
+	Suggestions []notFoundSuggestion
+}
+
+// RenderNotFound renders the not found page for the given path, with suggestions for what might have been
+// meant. Path and package are the same as given to RenderFile, with an empty path no suggestions are made.
+func (r *Renderer) RenderNotFound(pkg *types.Package, filePath string, writer io.Writer) error {
+	data := notFoundTemplateData{
+		layoutTemplateData: layoutTemplateData{
+			Title:           "Page not found",
+			CurrentFile:     filePath,
+			MarkdownContent: "",
+			CanonicalPath:   "",
+			markdownOptions: nil,
+		},
+		Suggestions: nil,
+	}
+
+	if pkg != nil {
+		suggestions, err := packageSuggestions(pkg, filePath)
+		if err != nil {
+			return err
+		}
+
+		data.Suggestions = suggestions
+	} else if filePath != "" {
+		data.Suggestions = r.contentSuggestions(filePath)
+	}
+
+	return r.executeTemplate(writer, pkg, "404.tmpl", data)
+}
+
+// contentSuggestions suggests packages with names similar to the first element of the path.
+func (r *Renderer) contentSuggestions(filePath string) []notFoundSuggestion {
+	name := strings.ToLower(strings.SplitN(filePath, "/", 2)[0])
+	ret := make([]notFoundSuggestion, 0)
+
+	for _, pkg := range r.packages {
+		target := strings.ToLower(pkg.TargetName)
+		maxDistance := len(target) / 3
+
+		if strings.Contains(name, target) || strings.Contains(target, name) || levenshtein(name, target) <= maxDistance {
+			ret = append(ret, notFoundSuggestion{
+				Prefix:   "Did you mean",
+				LinkText: path.Join("go.anx.io", pkg.TargetName),
+				Link:     "/" + pkg.TargetName + "/",
+				Suffix:   "?",
+			})
+		}
+	}
+
+	return ret
+}
+
+// packageSuggestions points to the latest version if the requested version or major version does not exist,
+// to the packages README otherwise.
+func packageSuggestions(pkg *types.Package, filePath string) ([]notFoundSuggestion, error) {
+	majorVersion, filePath, moduleVersions := splitMajorVersion(pkg, filePath)
+
+	// paths without major version are for v0 and v1, which might not exist either
+	requestedMajor := strings.TrimSuffix(majorVersionPrefixRegex.FindString(filePath), "/")
+	if requestedMajor == "" && len(moduleVersions) == 0 {
+		requestedMajor = "v1"
+	}
+
+	if highestMajor, ok := highestMajorVersion(pkg); ok && majorVersion == "" && requestedMajor != "" {
+		return []notFoundSuggestion{{
+			Prefix:   fmt.Sprintf("Major version %v of go.anx.io/%v does not exist, the latest one is", requestedMajor, pkg.TargetName),
+			LinkText: path.Join("go.anx.io", pkg.TargetName, highestMajor),
+			Link:     canonicalPackagePath(pkg, highestMajor, ""),
+			Suffix:   ".",
+		}}, nil
+	}
+
+	pathAndVersion := strings.SplitN(filePath, "@", 2)
+
+	if len(pathAndVersion) == 2 && !contains(moduleVersions, pathAndVersion[1]) {
+		latest, err := latestVersion(pkg, majorVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		if latest == "" {
+			return readmeSuggestion(pkg, majorVersion), nil
+		}
+
+		return []notFoundSuggestion{{
+			Prefix:   fmt.Sprintf("Version %v of %v does not exist, the latest version is", pathAndVersion[1], path.Join("go.anx.io", pkg.TargetName, majorVersion)),
+			LinkText: latest,
+			Link:     canonicalPackagePath(pkg, majorVersion, pathAndVersion[0]),
+			Suffix:   ".",
+		}}, nil
+	}
+
+	return readmeSuggestion(pkg, majorVersion), nil
+}
+
+func readmeSuggestion(pkg *types.Package, majorVersion string) []notFoundSuggestion {
+	return []notFoundSuggestion{{
+		Prefix:   "Have a look at the documentation of",
+		LinkText: path.Join("go.anx.io", pkg.TargetName, majorVersion),
+		Link:     canonicalPackagePath(pkg, majorVersion, ""),
+		Suffix:   ".",
+	}}
+}
+
+// highestMajorVersion returns the highest major version of the package with any versions, false if it has none.
+func highestMajorVersion(pkg *types.Package) (string, bool) {
+	for _, major := range pkg.FileReader.MajorVersions() {
+		if len(pkg.FileReader.Versions(major)) > 0 {
+			return major, true
+		}
+	}
+
+	return "", false
+}
+
+func contains(list []string, wanted string) bool {
+	for _, e := range list {
+		if e == wanted {
+			return true
+		}
+	}
+
+	return false
+}
+
+// levenshtein returns the edit distance between two strings.
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,151 @@
+package render_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/anexia-it/go.anx.io/pkg/render"
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+func TestIsNotFound(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		label    string
+		err      error
+		notFound bool
+	}{
+		{"nil", nil, false},
+		{"not found", render.ErrNotFound, true},
+		{"wrapped not found", fmt.Errorf("%w: unknown asset", render.ErrNotFound), true},
+		{"missing file", fmt.Errorf("error reading file: %w", types.ErrFileNotFound), true},
+		{"missing version", fmt.Errorf("error retrieving info: %w", types.ErrVersionNotFound), true},
+		{"internal error", errors.New("error executing template"), false},
+		{"wrapped internal error", fmt.Errorf("error rendering: %w", errors.New("broken")), false},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			if notFound := render.IsNotFound(testCase.err); notFound != testCase.notFound {
+				t.Errorf("expected IsNotFound to return %v for %v, got %v", testCase.notFound, testCase.err, notFound)
+			}
+		})
+	}
+}
+
+func TestRenderNotFoundSuggestions(t *testing.T) {
+	t.Parallel()
+
+	example := examplePackage(exampleFiles)
+	v2Only := packageWithVersions("v2only", map[string][]string{"v2": {"v2.0.0"}})
+	unreleased := packageWithVersions("unreleased", map[string][]string{"": nil})
+
+	renderer := newRenderer(t, example, v2Only, unreleased)
+
+	testCases := []struct {
+		label    string
+		pkg      *types.Package
+		filePath string
+		contains []string
+	}{
+		{"content without path", nil, "", []string{"Page not found"}},
+		{"similar package", nil, "exampel/foo", []string{`Did you mean <a href="/example/">go.anx.io/example</a>?`}},
+		{
+			"unknown major version",
+			example,
+			"v3/README.md",
+			[]string{`Major version v3 of go.anx.io/example does not exist, the latest one is <a href="/example/">go.anx.io/example</a>.`},
+		},
+		{
+			"only higher major versions",
+			v2Only,
+			"README.md",
+			[]string{`Major version v1 of go.anx.io/v2only does not exist, the latest one is <a href="/v2only/v2/">go.anx.io/v2only/v2</a>.`},
+		},
+		{
+			"unknown version",
+			example,
+			"example.go@v9.0.0",
+			[]string{`Version v9.0.0 of go.anx.io/example does not exist, the latest version is <a href="/example/example.go">v1.1.0</a>.`},
+		},
+		{
+			"unknown version without releases",
+			unreleased,
+			"README.md@v1.0.0",
+			[]string{`Have a look at the documentation of <a href="/unreleased/">go.anx.io/unreleased</a>.`},
+		},
+		{
+			"unknown major version without versions",
+			unreleased,
+			"v2/README.md",
+			[]string{`Have a look at the documentation of <a href="/unreleased/">go.anx.io/unreleased</a>.`},
+		},
+		{
+			"missing file",
+			v2Only,
+			"v2/missing.go",
+			[]string{`Have a look at the documentation of <a href="/v2only/v2/">go.anx.io/v2only/v2</a>.`},
+		},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			buffer := bytes.Buffer{}
+			if err := renderer.RenderNotFound(testCase.pkg, testCase.filePath, &buffer); err != nil {
+				t.Fatalf("error rendering not found page: %v", err)
+			}
+
+			for _, expected := range testCase.contains {
+				if !strings.Contains(buffer.String(), expected) {
+					t.Errorf("expected %q in not found page:\n%v", expected, buffer.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRenderFileNotFound(t *testing.T) {
+	t.Parallel()
+
+	v2Only := packageWithVersions("v2only", map[string][]string{"v2": {"v2.0.0"}})
+	renderer := newRenderer(t, v2Only)
+
+	testCases := []struct {
+		filePath string
+		notFound bool
+	}{
+		{"", true},
+		{"README.md", true},
+		{"releases.html", true},
+		{"v3/x", true},
+		{"v2/", false},
+		{"v2/README.md@v2.0.0", false},
+		{"v2/README.md@v2.1.0", true},
+		{"v2/releases.html", false},
+		{"v2/missing.go", true},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.filePath, func(t *testing.T) {
+			t.Parallel()
+
+			err := renderer.RenderFile(v2Only, testCase.filePath, &bytes.Buffer{})
+			if testCase.notFound && !render.IsNotFound(err) {
+				t.Errorf("expected not found error, got %v", err)
+			} else if !testCase.notFound && err != nil {
+				t.Errorf("error rendering %q: %v", testCase.filePath, err)
+			}
+		})
+	}
+}
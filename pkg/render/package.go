@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/anexia-it/go.anx.io/pkg/markdown"
+	"github.com/anexia-it/go.anx.io/pkg/symbols"
 	"github.com/anexia-it/go.anx.io/pkg/types"
 )
 
@@ -17,82 +18,174 @@ type packageTemplateData struct {
 	Package        *types.Package
 	CurrentVersion string
 	MajorVersion   string
-}
 
-func (r *Renderer) renderPackageFile(pkg *types.Package, filePath string, writer io.Writer) error {
-	var (
-		moduleVersions []string
-		majorVersion   string
-	)
+	// Release holds the release notes of CurrentVersion, only set for README files.
+	Release *releaseTemplateData
 
-	majorVersionPrefixRegex := regexp.MustCompile(`^v\d+(/|$)`)
-	if mv := majorVersionPrefixRegex.FindString(filePath); mv != "" {
-		majorVersion = strings.TrimSuffix(mv, "/")
-		moduleVersions = pkg.FileReader.Versions(majorVersion)
+	// Releases holds the release notes of every version of the major version, for the release history.
+	Releases         []*releaseTemplateData
+	IsReleaseHistory bool
 
-		if len(moduleVersions) > 0 {
-			filePath = strings.TrimPrefix(filePath, mv)
-		}
+	// Comparison is set for pages comparing two versions.
+	Comparison *compareTemplateData
+}
+
+// IsVersionedFile is true for pages showing a file of a single version, in contrast to pages like the
+// release history.
+func (d packageTemplateData) IsVersionedFile() bool {
+	return !d.IsReleaseHistory && d.Comparison == nil
+}
+
+// FileVersions returns the versions of the given major version CurrentFile is rendered for, source files
+// are only rendered for the latest version.
+func (d packageTemplateData) FileVersions(major string) []string {
+	versions := d.Package.FileReader.Versions(major)
+	if path.Ext(d.CurrentFile) == ".go" && len(versions) > 0 {
+		return versions[:1]
 	}
 
-	if len(moduleVersions) == 0 {
-		moduleVersions = pkg.FileReader.Versions("")
+	return versions
+}
+
+// majorVersionPrefixRegex matches the major version at the start of a path in a package.
+var majorVersionPrefixRegex = regexp.MustCompile(`^v\d+(/|$)`)
+
+func (r *Renderer) renderPackageFile(pkg *types.Package, filePath string, writer io.Writer) error {
+	if filePath == releaseFeedFile {
+		return r.renderReleaseFeed(pkg, writer)
 	}
 
+	majorVersion, filePath, moduleVersions := splitMajorVersion(pkg, filePath)
+
 	pathAndVersion := strings.SplitN(filePath, "@", 2)
 	filePath = pathAndVersion[0]
 
+	_, _, isComparison := parseComparePath(filePath)
+	if len(pathAndVersion) == 2 && (filePath == badgeFile || filePath == releaseHistoryFile || isComparison) {
+		// those pages cover the whole major version, a version would just be ignored
+		return fmt.Errorf("%w: %q has no versions", ErrNotFound, filePath)
+	}
+
+	// the badge of the package shows its highest major version, even without v0 and v1
+	if filePath == badgeFile {
+		return r.renderBadge(pkg, majorVersion, writer)
+	} else if filePath == releaseHistoryFile {
+		return r.renderReleaseHistory(pkg, majorVersion, moduleVersions, writer)
+	}
+
+	if len(moduleVersions) == 0 {
+		return fmt.Errorf("%w: no versions of major version %q of package %q", ErrNotFound, majorVersion, pkg.TargetName)
+	}
+
 	version := moduleVersions[0]
 
 	if len(pathAndVersion) == 2 {
 		version = pathAndVersion[1]
+
+		if path.Ext(filePath) == ".go" && version != moduleVersions[0] {
+			// matching the generated pages, see filesForPackage
+			return fmt.Errorf("%w: source files are only rendered for the latest version %q", ErrNotFound, moduleVersions[0])
+		}
+	}
+
+	if from, to, ok := parseComparePath(filePath); ok {
+		return r.renderComparison(pkg, majorVersion, from, to, writer)
 	}
 
 	if filePath == "" || filePath == "index.html" {
 		filePath = "README.md"
+	} else if dir, ok, err := r.importPathDir(pkg, version, filePath); err != nil {
+		return err
+	} else if ok {
+		return r.renderImportPath(pkg, majorVersion, version, dir, false, writer)
 	}
 
-	content, err := pkg.FileReader.ReadFile(filePath, version)
+	fileContent, err := pkg.FileReader.ReadFile(filePath, version)
 	if err != nil {
 		return fmt.Errorf("error reading file '%v' of version '%v': %w", filePath, version, err)
 	}
 
-	content, err = markdownContent(content, filePath)
+	content, err := markdownContent(fileContent, filePath)
 	if err != nil {
 		return fmt.Errorf("error retrieving markdown for package file: %w", err)
 	}
 
+	var release *releaseTemplateData
+	if filePath == "README.md" {
+		if release, err = r.releaseNotes(pkg, version); err != nil {
+			return err
+		}
+
+		if release != nil {
+			if release.PreviousVersion, err = previousRelease(pkg, majorVersion, version); err != nil {
+				return err
+			}
+
+			if err := r.addAPIChanges(pkg, release); err != nil {
+				return err
+			}
+		}
+	}
+
 	data := packageTemplateData{
 		layoutTemplateData: layoutTemplateData{
 			Title:           markdown.ExtractFirstHeader(content),
 			MarkdownContent: content,
 			CurrentFile:     filePath,
+			CanonicalPath:   canonicalPackagePath(pkg, majorVersion, filePath),
+			markdownOptions: []markdown.Option{
+				markdown.WithCodeLinker(r.codeLinker(pkg, version, filePath, fileContent)),
+			},
 		},
-		Package:        pkg,
-		CurrentVersion: version,
-		MajorVersion:   majorVersion,
+		Package:          pkg,
+		CurrentVersion:   version,
+		MajorVersion:     majorVersion,
+		Release:          release,
+		Releases:         nil,
+		IsReleaseHistory: false,
+		Comparison:       nil,
 	}
 
-	return r.executeTemplate(writer, "package.tmpl", data)
+	return r.executeTemplate(writer, pkg, "package.tmpl", data)
 }
 
-func (r *Renderer) filesForPackage(pkg *types.Package) []string {
+// splitMajorVersion splits the major version prefix off the path, returning the major version, the
+// remaining path and the versions of the major version. Paths not starting with a known major
+// version are for the default major version "".
+func splitMajorVersion(pkg *types.Package, filePath string) (string, string, []string) {
+	if mv := majorVersionPrefixRegex.FindString(filePath); mv != "" {
+		majorVersion := strings.TrimSuffix(mv, "/")
+
+		if moduleVersions := pkg.FileReader.Versions(majorVersion); len(moduleVersions) > 0 {
+			return majorVersion, strings.TrimPrefix(filePath, mv), moduleVersions
+		}
+	}
+
+	return "", filePath, pkg.FileReader.Versions("")
+}
+
+func (r *Renderer) filesForPackage(pkg *types.Package) ([]string, error) {
 	// files we want for every version
 	versionedFiles := []string{"README.md"}
 
 	majorVersions := pkg.FileReader.MajorVersions()
 
-	ret := make([]string, 0)
+	// the release feed covers all major versions
+	ret := []string{releaseFeedFile}
 
 	// for every major version we generate version "" (latest version) and all specific versions of it
 	for _, major := range majorVersions {
 		versions := []string{""}
 		versions = append(versions, pkg.FileReader.Versions(major)...)
 
-		majorFiles := make([]string, 0, (len(versions)*len(versionedFiles))+1)
+		majorFiles := make([]string, 0, (len(versions)*len(versionedFiles))+3)
 
-		// we always want index without version suffix
-		majorFiles = append(majorFiles, path.Join(major, "index.html"))
+		// we always want index without version suffix, the release history and the badge of the major version
+		majorFiles = append(majorFiles,
+			path.Join(major, "index.html"),
+			path.Join(major, releaseHistoryFile),
+			path.Join(major, badgeFile),
+		)
 
 		for _, v := range versions {
 			for _, filename := range versionedFiles {
@@ -104,8 +197,54 @@ func (r *Renderer) filesForPackage(pkg *types.Package) []string {
 			}
 		}
 
+		// compare pages for every release with its predecessor
+		releases, err := releasedVersions(pkg, major)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 1; i < len(releases); i++ {
+			majorFiles = append(majorFiles, path.Join(major, comparePath(releases[i], releases[i-1])))
+		}
+
+		// source files of the latest version only, identifiers of older versions link to pkg.go.dev
+		latestVersion := pkg.FileReader.Versions(major)[0]
+
+		sourceFiles, err := sourceFilesForVersion(pkg, latestVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, filename := range sourceFiles {
+			majorFiles = append(majorFiles,
+				path.Join(major, filename),
+				path.Join(major, fmt.Sprintf("%v@%v", filename, latestVersion)),
+			)
+		}
+
+		// pages for every import path in the module, for tools not walking up the import path
+		dirs, err := r.packageDirs(pkg, latestVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dir := range dirs {
+			majorFiles = append(majorFiles, path.Join(major, dir, "index.html"))
+		}
+
 		ret = append(ret, majorFiles...)
 	}
 
-	return ret
+	return ret, nil
+}
+
+// sourceFilesForVersion lists the Go source files in the given version of the package, we generate
+// pages for them to have targets for links to declarations.
+func sourceFilesForVersion(pkg *types.Package, version string) ([]string, error) {
+	files, err := pkg.FileReader.Files(version)
+	if err != nil {
+		return nil, fmt.Errorf("error listing files of version %q of package %q: %w", version, pkg.TargetName, err)
+	}
+
+	return symbols.SourceFiles(files), nil
 }
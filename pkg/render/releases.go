@@ -0,0 +1,247 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	gotypes "go/types"
+	"io"
+	"log"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/anexia-it/go.anx.io/pkg/apidiff"
+	"github.com/anexia-it/go.anx.io/pkg/changelog"
+	"github.com/anexia-it/go.anx.io/pkg/symbols"
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+// releaseHistoryFile is the file name the release history of every major version is rendered to.
+const releaseHistoryFile = "releases.html"
+
+type releaseTemplateData struct {
+	Version    string
+	Date       time.Time
+	TagMessage string
+
+	// PreviousVersion is the release before this one, only set in the release history and README pages.
+	PreviousVersion string
+
+	// APIChanges lists the changes of the exported API since PreviousVersion, BreaksSemver is set
+	// when incompatible changes were released without a new major version.
+	APIChanges   *apidiff.Report
+	BreaksSemver bool
+
+	// Changes is the markdown of the section for this version in CHANGELOG.md.
+	Changes string
+}
+
+// releaseNotes collects the tag message and changelog section for the given version, returning
+// nil for branches.
+func (r *Renderer) releaseNotes(pkg *types.Package, version string) (*releaseTemplateData, error) {
+	info, err := pkg.FileReader.VersionInfo(version)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving info for version %q: %w", version, err)
+	}
+
+	if info.IsBranch {
+		return nil, nil
+	}
+
+	ret := releaseTemplateData{
+		Version:    version,
+		Date:       info.Date,
+		TagMessage: info.TagMessage,
+		Changes:    "",
+
+		PreviousVersion: "",
+		APIChanges:      nil,
+		BreaksSemver:    false,
+	}
+
+	// not every package has a changelog, we just don't show one then
+	if contents, err := pkg.FileReader.ReadFile("CHANGELOG.md", version); err == nil {
+		if section := changelog.Parse(contents).Find(version); section != nil {
+			ret.Changes = section.Content
+		}
+	}
+
+	return &ret, nil
+}
+
+func (r *Renderer) renderReleaseHistory(pkg *types.Package, majorVersion string, moduleVersions []string, writer io.Writer) error {
+	if len(moduleVersions) == 0 {
+		return fmt.Errorf("%w: no versions of major version %q of package %q", ErrNotFound, majorVersion, pkg.TargetName)
+	}
+
+	releases := make([]*releaseTemplateData, 0, len(moduleVersions))
+
+	for _, version := range moduleVersions {
+		release, err := r.releaseNotes(pkg, version)
+		if err != nil {
+			return err
+		}
+
+		if release != nil {
+			if len(releases) > 0 {
+				releases[len(releases)-1].PreviousVersion = release.Version
+			}
+
+			releases = append(releases, release)
+		}
+	}
+
+	for _, release := range releases {
+		if err := r.addAPIChanges(pkg, release); err != nil {
+			return err
+		}
+	}
+
+	data := packageTemplateData{
+		layoutTemplateData: layoutTemplateData{
+			Title:           "Releases",
+			MarkdownContent: "",
+			CurrentFile:     releaseHistoryFile,
+			CanonicalPath:   canonicalPackagePath(pkg, majorVersion, releaseHistoryFile),
+			markdownOptions: nil,
+		},
+		Package:          pkg,
+		CurrentVersion:   moduleVersions[0],
+		MajorVersion:     majorVersion,
+		Release:          nil,
+		Releases:         releases,
+		IsReleaseHistory: true,
+		Comparison:       nil,
+	}
+
+	return r.executeTemplate(writer, pkg, "package.tmpl", data)
+}
+
+// apiComparison is the cached result of comparing the exported API of two versions.
+type apiComparison struct {
+	report       *apidiff.Report
+	breaksSemver bool
+}
+
+type apiComparisonKey struct {
+	pkg      *types.Package
+	from, to string
+}
+
+// addAPIChanges sets the API changes of the release since its PreviousVersion, if it has one.
+func (r *Renderer) addAPIChanges(pkg *types.Package, release *releaseTemplateData) error {
+	if release.PreviousVersion == "" {
+		return nil
+	}
+
+	comparison, err := r.compareAPI(pkg, release.PreviousVersion, release.Version)
+	if err != nil {
+		return err
+	}
+
+	release.APIChanges = comparison.report
+	release.BreaksSemver = comparison.breaksSemver
+
+	return nil
+}
+
+// compareAPI compares the exported API of two versions once, warning about incompatible changes without a new
+// major version.
+func (r *Renderer) compareAPI(pkg *types.Package, from, to string) (apiComparison, error) {
+	r.apiComparisonMutex.Lock()
+	defer r.apiComparisonMutex.Unlock()
+
+	key := apiComparisonKey{pkg: pkg, from: from, to: to}
+	if cached, ok := r.apiComparisonCache[key]; ok {
+		return cached, nil
+	}
+
+	report, err := r.apiChanges(pkg, from, to)
+	if err != nil {
+		return apiComparison{}, err
+	}
+
+	ret := apiComparison{
+		report:       report,
+		breaksSemver: report != nil && report.Incompatible() && !allowsIncompatibleChanges(from, to),
+	}
+
+	if ret.breaksSemver {
+		log.Printf(
+			"Warning: version %v of package %q has incompatible API changes to %v without a new major version",
+			to, pkg.TargetName, from,
+		)
+	}
+
+	r.apiComparisonCache[key] = ret
+
+	return ret, nil
+}
+
+// previousRelease returns the release before the given version in the major version, empty for the first one.
+func previousRelease(pkg *types.Package, majorVersion, version string) (string, error) {
+	releases, err := releasedVersions(pkg, majorVersion)
+	if err != nil {
+		return "", err
+	}
+
+	for i, release := range releases {
+		if release == version && i+1 < len(releases) {
+			return releases[i+1], nil
+		}
+	}
+
+	return "", nil
+}
+
+// apiChanges compares the exported API of two versions, returning nil if one of the versions is not a module.
+func (r *Renderer) apiChanges(pkg *types.Package, from, to string) (*apidiff.Report, error) {
+	fromIndex, err := r.symbols.Index(pkg, from)
+	if errors.Is(err, symbols.ErrNoModule) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error indexing version %q: %w", from, err)
+	}
+
+	toIndex, err := r.symbols.Index(pkg, to)
+	if errors.Is(err, symbols.ErrNoModule) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error indexing version %q: %w", to, err)
+	}
+
+	report := apidiff.Compare(packageTypes(fromIndex), packageTypes(toIndex))
+
+	return &report, nil
+}
+
+func packageTypes(idx *symbols.Index) []*gotypes.Package {
+	pkgs := idx.Packages()
+	ret := make([]*gotypes.Package, 0, len(pkgs))
+
+	for _, pkg := range pkgs {
+		ret = append(ret, pkg.Types)
+	}
+
+	return ret
+}
+
+// allowsIncompatibleChanges returns true if semver allows incompatible changes between the given versions,
+// which is the case for new major versions and new minor versions in major version 0.
+func allowsIncompatibleChanges(from, to string) bool {
+	fromVersion, err := semver.NewVersion(from)
+	if err != nil {
+		return true
+	}
+
+	toVersion, err := semver.NewVersion(to)
+	if err != nil {
+		return true
+	}
+
+	if fromVersion.Major() != toVersion.Major() {
+		return true
+	}
+
+	return toVersion.Major() == 0 && fromVersion.Minor() != toVersion.Minor()
+}
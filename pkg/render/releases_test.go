@@ -0,0 +1,126 @@
+package render_test
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/anexia-it/go.anx.io/pkg/render"
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+// versionedFileReader is a memoryFileReader with different files in some versions.
+type versionedFileReader struct {
+	memoryFileReader
+
+	versionFiles map[string]map[string]string
+}
+
+func (r versionedFileReader) ReadFile(path, version string) (string, error) {
+	files, ok := r.versionFiles[version]
+	if !ok {
+		return r.memoryFileReader.ReadFile(path, version)
+	}
+
+	if contents, ok := files[path]; ok {
+		return contents, nil
+	}
+
+	return "", fmt.Errorf("%w: %q in version %q", types.ErrFileNotFound, path, version)
+}
+
+func (r versionedFileReader) Files(version string) ([]string, error) {
+	files, ok := r.versionFiles[version]
+	if !ok {
+		return r.memoryFileReader.Files(version)
+	}
+
+	ret := make([]string, 0, len(files))
+	for file := range files {
+		ret = append(ret, file)
+	}
+
+	sort.Strings(ret)
+
+	return ret, nil
+}
+
+func TestSemverWarning(t *testing.T) {
+	t.Parallel()
+
+	// v1.0.0 has an additional function, removing it in v1.1.0 breaks compatibility
+	oldFiles := make(map[string]string, len(exampleFiles))
+	for name, contents := range exampleFiles {
+		oldFiles[name] = contents
+	}
+
+	oldFiles["example.go"] += "\n// Goodbye says goodbye.\nfunc Goodbye() string {\n\treturn \"goodbye\"\n}\n"
+
+	pkg := examplePackage(exampleFiles)
+	pkg.FileReader = versionedFileReader{
+		memoryFileReader: memoryFileReader{versions: map[string][]string{"": {"v1.1.0", "v1.0.0"}}, files: exampleFiles},
+		versionFiles:     map[string]map[string]string{"v1.0.0": oldFiles},
+	}
+
+	renderer := newRenderer(t, pkg)
+
+	testCases := []struct {
+		filePath string
+		warning  bool
+	}{
+		{"releases.html", true},
+		{"README.md@v1.1.0", true},
+		{"index.html", true},
+		{"README.md@v1.0.0", false},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.filePath, func(t *testing.T) {
+			t.Parallel()
+
+			buffer := bytes.Buffer{}
+			if err := renderer.RenderFile(pkg, testCase.filePath, &buffer); err != nil {
+				t.Fatalf("error rendering %q: %v", testCase.filePath, err)
+			}
+
+			if warning := bytes.Contains(buffer.Bytes(), []byte(`class="semverWarning"`)); warning != testCase.warning {
+				t.Errorf("expected semver warning %v, got %v", testCase.warning, warning)
+			}
+		})
+	}
+}
+
+func TestVersionedReleaseHistory(t *testing.T) {
+	t.Parallel()
+
+	pkg := examplePackage(exampleFiles)
+	renderer := newRenderer(t, pkg)
+
+	testCases := []struct {
+		filePath string
+		notFound bool
+	}{
+		{"releases.html", false},
+		{"releases.html@v1.0.0", true},
+		{"badge.svg", false},
+		{"badge.svg@v1.0.0", true},
+		{"compare/v1.0.0...v1.1.0.html", false},
+		{"compare/v1.0.0...v1.1.0.html@v1.0.0", true},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.filePath, func(t *testing.T) {
+			t.Parallel()
+
+			err := renderer.RenderFile(pkg, testCase.filePath, &bytes.Buffer{})
+			if testCase.notFound && !render.IsNotFound(err) {
+				t.Errorf("expected not found error, got %v", err)
+			} else if !testCase.notFound && err != nil {
+				t.Errorf("error rendering %q: %v", testCase.filePath, err)
+			}
+		})
+	}
+}
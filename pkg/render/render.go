@@ -1,36 +1,112 @@
 package render
 
 import (
+	"fmt"
 	"html/template"
 	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/anexia-it/go.anx.io/pkg/markdown"
+	"github.com/anexia-it/go.anx.io/pkg/symbols"
 	"github.com/anexia-it/go.anx.io/pkg/types"
 )
 
 type Renderer struct {
-	templates   map[string]*template.Template
-	packages    []*types.Package
-	contentPath string
+	templates map[string]*template.Template
+	packages  []*types.Package
 
-	version   string
-	sourceURL string
+	// packageTemplates holds the templates of packages with overrides, by TargetName.
+	packageTemplates map[string]map[string]*template.Template
+
+	content fs.FS
+	symbols *symbols.Indexer
+
+	searchIndexMutex sync.Mutex
+	searchIndexCache []searchEntry
+
+	packageDirsMutex sync.Mutex
+	packageDirsCache map[packageVersion][]string
+
+	apiComparisonMutex sync.Mutex
+	apiComparisonCache map[apiComparisonKey]apiComparison
+
+	assetMutex  sync.Mutex
+	staticFiles fs.FS
+	assetCache  map[string]string
+	cacheAssets bool
+
+	buildTimeMutex sync.Mutex
+	buildTime      time.Time
+
+	version     string
+	sourceURL   string
+	baseURL     string
+	precompress bool
+
+	codeStyle     string
+	darkCodeStyle string
 }
 
-func NewRenderer(templatePath string, contentPath string, packages []*types.Package) (*Renderer, error) {
-	templates, err := loadTemplates(templatePath)
-	if err != nil {
+// NewRenderer creates a Renderer for the given packages, using the templates and content files of the given file systems.
+func NewRenderer(templates fs.FS, content fs.FS, packages []*types.Package) (*Renderer, error) {
+	ret := &Renderer{
+		templates: nil,
+		packages:  packages,
+		content:   content,
+		symbols:   symbols.NewIndexer(packages),
+
+		packageTemplates: make(map[string]map[string]*template.Template),
+
+		searchIndexMutex: sync.Mutex{},
+		searchIndexCache: nil,
+
+		packageDirsMutex: sync.Mutex{},
+		packageDirsCache: make(map[packageVersion][]string),
+
+		apiComparisonMutex: sync.Mutex{},
+		apiComparisonCache: make(map[apiComparisonKey]apiComparison),
+
+		assetMutex:  sync.Mutex{},
+		staticFiles: nil,
+		assetCache:  make(map[string]string),
+		cacheAssets: true,
+
+		buildTimeMutex: sync.Mutex{},
+		buildTime:      time.Time{},
+
+		// those fields are set later
+		version:     "",
+		sourceURL:   "",
+		baseURL:     "",
+		precompress: false,
+
+		codeStyle:     markdown.DefaultCodeStyle,
+		darkCodeStyle: markdown.DefaultDarkCodeStyle,
+	}
+
+	var err error
+
+	if ret.templates, err = loadTemplates(templates, ret.templateFuncs()); err != nil {
 		return nil, err
 	}
 
-	return &Renderer{
-		templates:   templates,
-		packages:    packages,
-		contentPath: contentPath,
+	for _, pkg := range packages {
+		overridePattern := path.Join(packageTemplateDir(pkg), "*.tmpl")
 
-		// both those fields are set later
-		version:   "",
-		sourceURL: "",
-	}, nil
+		if overrides, err := fs.Glob(templates, overridePattern); err != nil || len(overrides) == 0 {
+			continue
+		}
+
+		if ret.packageTemplates[pkg.TargetName], err = loadTemplates(templates, ret.templateFuncs(), overridePattern); err != nil {
+			return nil, fmt.Errorf("error loading templates of package %q: %w", pkg.TargetName, err)
+		}
+	}
+
+	return ret, nil
 }
 
 func (r *Renderer) SetBuildInfo(version string, sourceURL string) {
@@ -38,6 +114,87 @@ func (r *Renderer) SetBuildInfo(version string, sourceURL string) {
 	r.sourceURL = sourceURL
 }
 
+// SetBuildTime sets the time shown as generation time on every page, defaulting to the date of the newest
+// commit of all packages to make generating the same inputs twice yield the same files.
+func (r *Renderer) SetBuildTime(buildTime time.Time) {
+	r.buildTimeMutex.Lock()
+	defer r.buildTimeMutex.Unlock()
+
+	r.buildTime = buildTime
+}
+
+// currentBuildTime returns the time set with SetBuildTime or the date of the newest commit.
+func (r *Renderer) currentBuildTime() (time.Time, error) {
+	r.buildTimeMutex.Lock()
+	defer r.buildTimeMutex.Unlock()
+
+	if r.buildTime.IsZero() {
+		newestCommit, err := r.newestCommitDate()
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		r.buildTime = newestCommit
+	}
+
+	return r.buildTime, nil
+}
+
+// newestCommitDate returns the date of the newest commit of the latest versions of all packages.
+func (r *Renderer) newestCommitDate() (time.Time, error) {
+	var newestCommit time.Time
+
+	for _, pkg := range r.packages {
+		for _, major := range pkg.FileReader.MajorVersions() {
+			versions := pkg.FileReader.Versions(major)
+			if len(versions) == 0 {
+				continue
+			}
+
+			info, err := pkg.FileReader.VersionInfo(versions[0])
+			if err != nil {
+				return time.Time{}, fmt.Errorf("error retrieving info for latest version of package %q: %w", pkg.TargetName, err)
+			}
+
+			if info.CommitDate.After(newestCommit) {
+				newestCommit = info.CommitDate
+			}
+		}
+	}
+
+	return newestCommit.UTC(), nil
+}
+
+// SetPrecompress enables writing gzip compressed siblings of generated text files in GenerateFiles.
+func (r *Renderer) SetPrecompress(precompress bool) {
+	r.precompress = precompress
+}
+
+// SetCodeStyles sets the chroma styles for highlighted code, the dark one is used for browsers preferring a
+// dark color scheme and can be empty to always use the light one.
+func (r *Renderer) SetCodeStyles(style, darkStyle string) error {
+	if err := markdown.RenderCodeCSS(io.Discard, style, darkStyle); err != nil {
+		return fmt.Errorf("error checking code styles: %w", err)
+	}
+
+	r.assetMutex.Lock()
+	defer r.assetMutex.Unlock()
+
+	r.codeStyle = style
+	r.darkCodeStyle = darkStyle
+
+	// the fingerprint of the stylesheet changes with the styles
+	r.assetCache = make(map[string]string)
+
+	return nil
+}
+
+// SetBaseURL sets the URL the site is published at, used for links needing absolute URLs
+// like canonical links and the sitemap.
+func (r *Renderer) SetBaseURL(baseURL string) {
+	r.baseURL = strings.TrimSuffix(baseURL, "/")
+}
+
 func (r *Renderer) RenderFile(pkg *types.Package, filePath string, w io.Writer) error {
 	if pkg == nil {
 		return r.renderContentFile(filePath, w)
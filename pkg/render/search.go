@@ -0,0 +1,146 @@
+package render
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/anexia-it/go.anx.io/pkg/markdown"
+	"github.com/anexia-it/go.anx.io/pkg/symbols"
+)
+
+const (
+	searchIndexFile = "search.json"
+	searchPageFile  = "search.html"
+)
+
+// searchEntry is a single searchable item, the JSON keys are kept short to keep the index compact.
+type searchEntry struct {
+	// Kind is one of "package", "heading" or "symbol".
+	Kind    string `json:"k"`
+	Title   string `json:"t"`
+	Text    string `json:"d,omitempty"`
+	Package string `json:"p"`
+	URL     string `json:"u"`
+}
+
+// searchIndex returns the entries for searching the latest version of every major version of our packages.
+// The index is built once and cached afterwards.
+func (r *Renderer) searchIndex() ([]searchEntry, error) {
+	r.searchIndexMutex.Lock()
+	defer r.searchIndexMutex.Unlock()
+
+	if r.searchIndexCache != nil {
+		return r.searchIndexCache, nil
+	}
+
+	ret := make([]searchEntry, 0)
+
+	for _, pkg := range r.packages {
+		for _, major := range pkg.FileReader.MajorVersions() {
+			versions := pkg.FileReader.Versions(major)
+			if len(versions) == 0 {
+				continue
+			}
+
+			version := versions[0]
+			packageName := path.Join("go.anx.io", pkg.TargetName, major)
+			packageURL := "/" + path.Join(pkg.TargetName, major) + "/"
+
+			ret = append(ret, searchEntry{
+				Kind:    "package",
+				Title:   packageName,
+				Text:    pkg.Summary,
+				Package: packageName,
+				URL:     packageURL,
+			})
+
+			if readme, err := pkg.FileReader.ReadFile("README.md", version); err == nil {
+				for _, heading := range markdown.ExtractHeadings(readme) {
+					ret = append(ret, searchEntry{
+						Kind:    "heading",
+						Title:   heading.Text,
+						Text:    "",
+						Package: packageName,
+						URL:     packageURL + "#" + heading.ID,
+					})
+				}
+			}
+
+			idx, err := r.symbols.Index(pkg, version)
+			if errors.Is(err, symbols.ErrNoModule) {
+				continue
+			} else if err != nil {
+				return nil, fmt.Errorf("error indexing version %q of package %q: %w", version, pkg.TargetName, err)
+			}
+
+			for _, goPackage := range idx.Packages() {
+				if strings.Contains("/"+goPackage.ImportPath+"/", "/internal/") {
+					continue
+				}
+
+				for name, location := range goPackage.Exported() {
+					ret = append(ret, searchEntry{
+						Kind:    "symbol",
+						Title:   goPackage.Name + "." + name,
+						Text:    goPackage.ImportPath,
+						Package: packageName,
+						URL:     symbolURL(idx, "", location),
+					})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(ret, func(a, b int) bool {
+		if ret[a].Package != ret[b].Package {
+			return ret[a].Package < ret[b].Package
+		}
+
+		return ret[a].URL < ret[b].URL
+	})
+
+	r.searchIndexCache = ret
+
+	return ret, nil
+}
+
+func (r *Renderer) renderSearchIndex(writer io.Writer) error {
+	entries, err := r.searchIndex()
+	if err != nil {
+		return err
+	}
+
+	if err := json.NewEncoder(writer).Encode(entries); err != nil {
+		return fmt.Errorf("error encoding search index: %w", err)
+	}
+
+	return nil
+}
+
+// RenderSearchResults writes the entries of the search index matching the query as JSON.
+func (r *Renderer) RenderSearchResults(query string, writer io.Writer) error {
+	entries, err := r.searchIndex()
+	if err != nil {
+		return err
+	}
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	results := make([]searchEntry, 0)
+
+	for _, entry := range entries {
+		if strings.Contains(strings.ToLower(entry.Title), query) || strings.Contains(strings.ToLower(entry.Text), query) {
+			results = append(results, entry)
+		}
+	}
+
+	if err := json.NewEncoder(writer).Encode(results); err != nil {
+		return fmt.Errorf("error encoding search results: %w", err)
+	}
+
+	return nil
+}
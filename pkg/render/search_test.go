@@ -0,0 +1,105 @@
+package render_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type searchEntry struct {
+	Kind    string `json:"k"`
+	Title   string `json:"t"`
+	Text    string `json:"d"`
+	Package string `json:"p"`
+	URL     string `json:"u"`
+}
+
+func TestSearchIndex(t *testing.T) {
+	t.Parallel()
+
+	renderer := newRenderer(t, examplePackage(exampleFiles), packageWithVersions("unreleased", map[string][]string{"": nil}))
+
+	buffer := bytes.Buffer{}
+	if err := renderer.RenderFile(nil, "search.json", &buffer); err != nil {
+		t.Fatalf("error rendering search index: %v", err)
+	}
+
+	var entries []searchEntry
+	if err := json.Unmarshal(buffer.Bytes(), &entries); err != nil {
+		t.Fatalf("error decoding search index: %v", err)
+	}
+
+	testCases := []searchEntry{
+		{Kind: "package", Title: "go.anx.io/example", Text: "Example package", Package: "go.anx.io/example", URL: "/example/"},
+		{Kind: "heading", Title: "Usage", Text: "", Package: "go.anx.io/example", URL: "/example/#usage"},
+		{Kind: "symbol", Title: "example.Hello", Text: "go.anx.io/example", Package: "go.anx.io/example", URL: "/example/example.go@v1.1.0#Hello"},
+		{Kind: "symbol", Title: "client.Client", Text: "go.anx.io/example/client", Package: "go.anx.io/example", URL: "/example/client/client.go@v1.1.0#Client"},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.Title, func(t *testing.T) {
+			t.Parallel()
+
+			for _, entry := range entries {
+				if entry == testCase {
+					return
+				}
+			}
+
+			t.Errorf("expected %+v in search index %+v", testCase, entries)
+		})
+	}
+
+	for _, entry := range entries {
+		if entry.Package != "go.anx.io/example" {
+			t.Errorf("unexpected entry %+v of package without versions", entry)
+		}
+	}
+}
+
+func TestRenderSearchResults(t *testing.T) {
+	t.Parallel()
+
+	renderer := newRenderer(t, examplePackage(exampleFiles))
+
+	testCases := []struct {
+		query  string
+		titles []string
+	}{
+		{"hello", []string{"example.Hello"}},
+		{"CLIENT", []string{"client.Client"}},
+		{"  usage ", []string{"Usage"}},
+		{"example package", []string{"go.anx.io/example"}},
+		{"nothing", []string{}},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.query, func(t *testing.T) {
+			t.Parallel()
+
+			buffer := bytes.Buffer{}
+			if err := renderer.RenderSearchResults(testCase.query, &buffer); err != nil {
+				t.Fatalf("error rendering search results: %v", err)
+			}
+
+			var results []searchEntry
+			if err := json.Unmarshal(buffer.Bytes(), &results); err != nil {
+				t.Fatalf("error decoding search results: %v", err)
+			} else if results == nil {
+				t.Fatalf("expected a JSON array, got %q", buffer.String())
+			}
+
+			titles := make([]string, 0, len(results))
+			for _, result := range results {
+				titles = append(titles, result.Title)
+			}
+
+			if strings.Join(titles, ",") != strings.Join(testCase.titles, ",") {
+				t.Errorf("%v (actual) did not match %v (expected)", titles, testCase.titles)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package render
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+const (
+	sitemapFile = "sitemap.xml"
+	robotsFile  = "robots.txt"
+)
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Location     string `xml:"loc"`
+	LastModified string `xml:"lastmod,omitempty"`
+}
+
+// canonicalPackagePath returns the path of the page showing the latest version of the file, which is what
+// search engines should index instead of the version-pinned pages.
+func canonicalPackagePath(pkg *types.Package, majorVersion, filePath string) string {
+	if filePath == "README.md" {
+		filePath = ""
+	}
+
+	ret := "/" + path.Join(pkg.TargetName, majorVersion, filePath)
+	if filePath == "" {
+		ret += "/"
+	}
+
+	return ret
+}
+
+// renderSitemap lists the canonical pages of the site, the README and release history of every major
+// version of every package with the date of the latest commit as modification date.
+func (r *Renderer) renderSitemap(writer io.Writer) error {
+	urlSet := sitemapURLSet{
+		XMLName: xml.Name{Space: "", Local: ""},
+		URLs:    make([]sitemapURL, 0),
+	}
+
+	newestCommit, err := r.newestCommitDate()
+	if err != nil {
+		return err
+	}
+
+	for _, pkg := range r.packages {
+		for _, major := range pkg.FileReader.MajorVersions() {
+			versions := pkg.FileReader.Versions(major)
+			if len(versions) == 0 {
+				continue
+			}
+
+			info, err := pkg.FileReader.VersionInfo(versions[0])
+			if err != nil {
+				return fmt.Errorf("error retrieving info for latest version of package %q: %w", pkg.TargetName, err)
+			}
+
+			lastModified := info.CommitDate.UTC().Format(time.RFC3339)
+
+			urlSet.URLs = append(urlSet.URLs,
+				sitemapURL{r.baseURL + canonicalPackagePath(pkg, major, "README.md"), lastModified},
+				sitemapURL{r.baseURL + canonicalPackagePath(pkg, major, releaseHistoryFile), lastModified},
+			)
+		}
+	}
+
+	contentFiles, err := r.filesForContent()
+	if err != nil {
+		return err
+	}
+
+	for _, file := range contentFiles {
+		if ext := path.Ext(file); (ext != ".html" && ext != ".md") || file == notFoundFile {
+			continue
+		}
+
+		contentURL := sitemapURL{r.baseURL + "/" + file, ""}
+
+		if file == "index.html" {
+			// the index lists our packages, so it changes whenever one of them changes
+			contentURL.Location = r.baseURL + "/"
+			contentURL.LastModified = newestCommit.UTC().Format(time.RFC3339)
+		}
+
+		urlSet.URLs = append(urlSet.URLs, contentURL)
+	}
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return fmt.Errorf("error writing sitemap: %w", err)
+	}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(urlSet); err != nil {
+		return fmt.Errorf("error encoding sitemap: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Renderer) renderRobotsTxt(writer io.Writer) error {
+	if _, err := fmt.Fprintf(writer, "User-agent: *\nAllow: /\n\nSitemap: %v/%v\n", r.baseURL, sitemapFile); err != nil {
+		return fmt.Errorf("error writing robots.txt: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package render_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestSitemap(t *testing.T) {
+	t.Parallel()
+
+	renderer := newRenderer(t,
+		examplePackage(exampleFiles),
+		packageWithVersions("multi", map[string][]string{"": {"v1.0.0"}, "v2": {"v2.0.0"}}),
+		packageWithVersions("unreleased", map[string][]string{"": nil}),
+	)
+	renderer.SetBaseURL("https://go.anx.io/")
+
+	buffer := bytes.Buffer{}
+	if err := renderer.RenderFile(nil, "sitemap.xml", &buffer); err != nil {
+		t.Fatalf("error rendering sitemap: %v", err)
+	}
+
+	var urlSet struct {
+		XMLName xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+		URLs    []struct {
+			Location     string `xml:"loc"`
+			LastModified string `xml:"lastmod"`
+		} `xml:"url"`
+	}
+
+	if err := xml.Unmarshal(buffer.Bytes(), &urlSet); err != nil {
+		t.Fatalf("error decoding sitemap: %v", err)
+	}
+
+	lastModified := make(map[string]string)
+	for _, url := range urlSet.URLs {
+		lastModified[url.Location] = url.LastModified
+	}
+
+	testCases := []struct {
+		location     string
+		lastModified string
+	}{
+		{"https://go.anx.io/", "2024-01-10T12:00:00Z"},
+		{"https://go.anx.io/example/", "2024-01-10T12:00:00Z"},
+		{"https://go.anx.io/example/releases.html", "2024-01-10T12:00:00Z"},
+		{"https://go.anx.io/multi/", "2024-01-09T12:00:00Z"},
+		{"https://go.anx.io/multi/releases.html", "2024-01-09T12:00:00Z"},
+		{"https://go.anx.io/multi/v2/", "2024-01-10T12:00:00Z"},
+		{"https://go.anx.io/multi/v2/releases.html", "2024-01-10T12:00:00Z"},
+		{"https://go.anx.io/search.html", ""},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.location, func(t *testing.T) {
+			t.Parallel()
+
+			if actual, ok := lastModified[testCase.location]; !ok {
+				t.Errorf("expected %v in sitemap:\n%v", testCase.location, buffer.String())
+			} else if actual != testCase.lastModified {
+				t.Errorf("%q (actual) did not match %q (expected)", actual, testCase.lastModified)
+			}
+		})
+	}
+
+	if len(urlSet.URLs) != len(testCases) {
+		t.Errorf("expected only the pages above in sitemap:\n%v", buffer.String())
+	}
+}
+
+func TestRobotsTxt(t *testing.T) {
+	t.Parallel()
+
+	renderer := newRenderer(t, examplePackage(exampleFiles))
+	renderer.SetBaseURL("https://go.anx.io/")
+
+	buffer := bytes.Buffer{}
+	if err := renderer.RenderFile(nil, "robots.txt", &buffer); err != nil {
+		t.Fatalf("error rendering robots.txt: %v", err)
+	}
+
+	expected := "User-agent: *\nAllow: /\n\nSitemap: https://go.anx.io/sitemap.xml\n"
+	if buffer.String() != expected {
+		t.Errorf("%q (actual) did not match %q (expected)", buffer.String(), expected)
+	}
+}
+
+func TestCanonicalLink(t *testing.T) {
+	t.Parallel()
+
+	multi := packageWithVersions("multi", map[string][]string{"": {"v1.0.0"}, "v2": {"v2.1.0", "v2.0.0"}})
+
+	renderer := newRenderer(t, multi)
+	renderer.SetBaseURL("https://go.anx.io")
+
+	testCases := []struct {
+		file      string
+		canonical string
+	}{
+		{"README.md", "https://go.anx.io/multi/"},
+		{"README.md@v1.0.0", "https://go.anx.io/multi/"},
+		{"v2/README.md@v2.0.0", "https://go.anx.io/multi/v2/"},
+		{"v2/example.go@v2.1.0", "https://go.anx.io/multi/v2/example.go"},
+		{"v2/releases.html", "https://go.anx.io/multi/v2/releases.html"},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.file, func(t *testing.T) {
+			t.Parallel()
+
+			buffer := bytes.Buffer{}
+			if err := renderer.RenderFile(multi, testCase.file, &buffer); err != nil {
+				t.Fatalf("error rendering %v: %v", testCase.file, err)
+			}
+
+			expected := `<link rel="canonical" href="` + testCase.canonical + `">`
+			if !strings.Contains(buffer.String(), expected) {
+				t.Errorf("expected %v in:\n%v", expected, buffer.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/anexia-it/go.anx.io/pkg/forge"
+)
+
+type sourceLinksTemplateData struct {
+	Repository string
+
+	// Directory and Line are the templates for the go-source meta tag, with {dir}, {/dir}, {file} and
+	// {line} placeholders.
+	Directory string
+	Line      string
+
+	// File links to the current file, or the repository root for pages not showing a single file.
+	File string
+}
+
+// SourceLinks returns the links into the source repository for the current version.
+func (d packageTemplateData) SourceLinks() (sourceLinksTemplateData, error) {
+	templates, err := forge.Templates(d.Package)
+	if err != nil {
+		return sourceLinksTemplateData{}, fmt.Errorf("error retrieving source URL templates: %w", err)
+	}
+
+	info, err := d.Package.FileReader.VersionInfo(d.CurrentVersion)
+	if err != nil {
+		return sourceLinksTemplateData{}, fmt.Errorf("error retrieving info for version %q: %w", d.CurrentVersion, err)
+	}
+
+	directory := forge.Expand(templates.Directory, d.Package.Source, d.CurrentVersion, info.Commit)
+
+	ret := sourceLinksTemplateData{
+		Repository: forge.Repository(d.Package.Source),
+		Directory:  directory,
+		Line:       forge.Expand(templates.Line, d.Package.Source, d.CurrentVersion, info.Commit),
+		File:       forge.ExpandFile(directory, "", 0),
+	}
+
+	if d.IsVersionedFile() {
+		ret.File = forge.ExpandFile(forge.Expand(templates.File, d.Package.Source, d.CurrentVersion, info.Commit), d.CurrentFile, 0)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,105 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/anexia-it/go.anx.io/pkg/markdown"
+	"github.com/anexia-it/go.anx.io/pkg/symbols"
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+// codeLinker creates a markdown.CodeLinker linking identifiers in Go code blocks to their declarations.
+// For Go source files, the whole file is type checked and every identifier is linked, for other files
+// we only link references to exported identifiers of the package.
+func (r *Renderer) codeLinker(pkg *types.Package, version, filePath, fileContent string) markdown.CodeLinker {
+	idx, err := r.symbols.Index(pkg, version)
+	if err != nil {
+		if !errors.Is(err, symbols.ErrNoModule) {
+			log.Printf("Not linking symbols for version %q of package %q: %v", version, pkg.TargetName, err)
+		}
+
+		return nil
+	}
+
+	isSourceFile := path.Ext(filePath) == ".go"
+	fileContent = strings.TrimRight(strings.ReplaceAll(fileContent, "\r\n", "\n"), "\n")
+
+	return func(language, code string) []markdown.CodeAnnotation {
+		if language != "go" {
+			return nil
+		}
+
+		var refs []symbols.Reference
+
+		if isSourceFile {
+			// the source file is rendered as a single code block, the references are only valid for that
+			if strings.TrimRight(code, "\n") != fileContent {
+				return nil
+			}
+
+			refs = idx.References(filePath)
+		} else {
+			refs = idx.SnippetReferences(code)
+		}
+
+		ret := make([]markdown.CodeAnnotation, 0, len(refs))
+
+		for _, ref := range refs {
+			annotation := markdown.CodeAnnotation{
+				Offset: ref.Offset,
+				Length: ref.Length,
+				ID:     ref.ID,
+				Href:   "",
+			}
+
+			if ref.Target != nil {
+				annotation.Href = symbolURL(idx, filePath, ref.Target)
+			} else if ref.ID != "" {
+				annotation.Href = "#" + ref.ID
+			}
+
+			ret = append(ret, annotation)
+		}
+
+		return ret
+	}
+}
+
+// symbolURL returns the URL to the declaration of the target, linking to the rendered source files of the latest
+// versions of our packages and to pkg.go.dev for everything else.
+func symbolURL(current *symbols.Index, currentFile string, target *symbols.Location) string {
+	if target.Module == nil {
+		return pkgGoDevURL(target.ImportPath, target.ID)
+	}
+
+	module := target.Module
+
+	if versions := module.Package.FileReader.Versions(module.Major); len(versions) == 0 || versions[0] != module.Version {
+		// source files are only rendered for the latest version
+		return pkgGoDevURL(target.ImportPath+"@"+module.Version, target.ID)
+	}
+
+	anchor := target.ID
+	if anchor == "" {
+		// source files are rendered as a single code block, making this the anchor of the line
+		anchor = fmt.Sprintf("code-1-%v", target.Line)
+	}
+
+	if target.Module == current && target.File == currentFile {
+		return "#" + anchor
+	}
+
+	return fmt.Sprintf("/%v@%v#%v", path.Join(module.Package.TargetName, module.Major, target.File), module.Version, anchor)
+}
+
+func pkgGoDevURL(importPath, id string) string {
+	if id == "" {
+		return "https://pkg.go.dev/" + importPath
+	}
+
+	return fmt.Sprintf("https://pkg.go.dev/%v#%v", importPath, id)
+}
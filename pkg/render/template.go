@@ -4,34 +4,58 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
-	"os"
 	"path"
 	"time"
 
 	"html/template"
 
 	"github.com/anexia-it/go.anx.io/pkg/markdown"
+	"github.com/anexia-it/go.anx.io/pkg/types"
 )
 
 type layoutTemplateData struct {
 	Title           string
 	CurrentFile     string
 	MarkdownContent string
+
+	// CanonicalPath is the path of the page search engines should index for this page.
+	CanonicalPath string
+
+	markdownOptions []markdown.Option
+}
+
+// RenderedMarkdown renders MarkdownContent to HTML, with the options given by the page.
+func (d layoutTemplateData) RenderedMarkdown() (template.HTML, error) {
+	//nolint:wrapcheck // called from a template, error already has enough context
+	return markdown.RenderMarkdown(d.MarkdownContent, append([]markdown.Option{markdown.WithCopyButton("Copy")}, d.markdownOptions...)...)
+}
+
+// TableOfContents returns the table of contents of MarkdownContent for the sidebar, nil for documents with less
+// than two headings, which don't need one.
+func (d layoutTemplateData) TableOfContents() []*markdown.TOCEntry {
+	toc := markdown.ExtractTableOfContents(d.MarkdownContent)
+	if len(toc) == 0 || (len(toc) == 1 && len(toc[0].Children) == 0) {
+		return nil
+	}
+
+	return toc
 }
 
 type commonTemplateData struct {
+	// CurrentTime is the time the site was generated, see Renderer.SetBuildTime.
 	CurrentTime time.Time
 	PageData    interface{}
 
 	Version   string
 	SourceURL string
+	BaseURL   string
 }
 
-func loadTemplates(templatePath string) (map[string]*template.Template, error) {
-	baseTemplate, err := template.New("").Funcs(template.FuncMap{
-		"formatDate":          formatDate,
-		"renderMarkdown":      markdown.RenderMarkdown,
-		"removeGitRepoSuffix": RemoveGitRepoSuffix,
+func (r *Renderer) templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"formatDate":     formatDate,
+		"renderMarkdown": markdown.RenderMarkdown,
+		"asset":          r.asset,
 		"default": func(d string, v string) string {
 			if v == "" {
 				return d
@@ -39,18 +63,44 @@ func loadTemplates(templatePath string) (map[string]*template.Template, error) {
 
 			return v
 		},
-	}).ParseFiles(path.Join(templatePath, "layout.tmpl"))
+	}
+}
+
+// loadTemplates parses every page template in the root of templateFS, each together with layout.tmpl and the
+// partials in partials/*.tmpl. The templates matched by the override patterns are parsed last, so their
+// definitions replace the ones of the page templates and partials.
+func loadTemplates(templateFS fs.FS, funcs template.FuncMap, overridePatterns ...string) (map[string]*template.Template, error) {
+	baseTemplate, err := template.New("").Funcs(funcs).ParseFS(templateFS, "layout.tmpl")
 
 	if err != nil {
 		return nil, fmt.Errorf("error parsing layout template: %w", err)
 	}
 
-	files, err := fs.Glob(os.DirFS(templatePath), "*.tmpl")
+	if partials, err := fs.Glob(templateFS, "partials/*.tmpl"); err != nil {
+		return nil, fmt.Errorf("error searching partials: %w", err)
+	} else if len(partials) > 0 {
+		if _, err := baseTemplate.ParseFS(templateFS, partials...); err != nil {
+			return nil, fmt.Errorf("error parsing partials: %w", err)
+		}
+	}
+
+	files, err := fs.Glob(templateFS, "*.tmpl")
 
 	if err != nil {
 		return nil, fmt.Errorf("error searching templates: %w", err)
 	}
 
+	overrides := make([]string, 0)
+
+	for _, pattern := range overridePatterns {
+		matches, err := fs.Glob(templateFS, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("error searching override templates: %w", err)
+		}
+
+		overrides = append(overrides, matches...)
+	}
+
 	ret := make(map[string]*template.Template, len(files)-1)
 
 	for _, file := range files {
@@ -58,30 +108,52 @@ func loadTemplates(templatePath string) (map[string]*template.Template, error) {
 			continue
 		}
 
-		tmpl, err := template.Must(baseTemplate.Clone()).ParseFiles(
-			path.Join(templatePath, file),
-		)
+		tmpl, err := template.Must(baseTemplate.Clone()).ParseFS(templateFS, file)
 
 		if err != nil {
 			return nil, fmt.Errorf("error parsing layout template: %w", err)
 		}
 
+		if len(overrides) > 0 {
+			if tmpl, err = tmpl.ParseFS(templateFS, overrides...); err != nil {
+				return nil, fmt.Errorf("error parsing override templates: %w", err)
+			}
+		}
+
 		ret[file] = tmpl
 	}
 
 	return ret, nil
 }
 
-func (r *Renderer) executeTemplate(destinationStream io.Writer, name string, data interface{}) error {
-	tmpl, ok := r.templates[name]
+// packageTemplateDir is the directory in the templates with the overrides of a package, containing
+// templates replacing the partials or blocks of the page templates.
+func packageTemplateDir(pkg *types.Package) string {
+	return path.Join("packages", pkg.TargetName)
+}
+
+// executeTemplate executes the named page template, using the overrides of the package if it has any.
+func (r *Renderer) executeTemplate(destinationStream io.Writer, pkg *types.Package, name string, data interface{}) error {
+	templates := r.templates
+	if pkg != nil && r.packageTemplates[pkg.TargetName] != nil {
+		templates = r.packageTemplates[pkg.TargetName]
+	}
+
+	tmpl, ok := templates[name]
 	if !ok {
 		return fmt.Errorf("requested template does not exist: %w", fs.ErrNotExist)
 	}
 
+	buildTime, err := r.currentBuildTime()
+	if err != nil {
+		return err
+	}
+
 	if err := tmpl.Execute(destinationStream, commonTemplateData{
-		CurrentTime: time.Now(),
+		CurrentTime: buildTime,
 		Version:     r.version,
 		SourceURL:   r.sourceURL,
+		BaseURL:     r.baseURL,
 		PageData:    data,
 	}); err != nil {
 		return fmt.Errorf("error executing template: %w", err)
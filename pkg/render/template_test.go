@@ -0,0 +1,82 @@
+package render_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/anexia-it/go.anx.io/pkg/render"
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+func templateFile(contents string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(contents)}
+}
+
+func TestPackageTemplateOverrides(t *testing.T) {
+	t.Parallel()
+
+	templates := fstest.MapFS{
+		"layout.tmpl":          templateFile(`{{ define "" }}[{{ block "header" . }}{{ end }}|{{ block "content" . }}{{ end }}]{{ end }}`),
+		"partials/header.tmpl": templateFile(`{{ define "packageHeader" }}default header of {{ .PageData.Package.TargetName }}{{ end }}`),
+		"package.tmpl": templateFile(`{{ define "header" }}{{ template "packageHeader" . }}{{ end }}` +
+			`{{ define "content" }}default content{{ end }}`),
+
+		// partials and blocks of page templates can be replaced per package
+		"packages/custom/header.tmpl":  templateFile(`{{ define "packageHeader" }}custom header{{ end }}`),
+		"packages/custom/content.tmpl": templateFile(`{{ define "content" }}custom content{{ end }}`),
+
+		// not one of our packages
+		"packages/unknown/header.tmpl": templateFile(`{{ define "packageHeader" }}unknown header{{ end }}`),
+	}
+
+	custom := packageWithVersions("custom", map[string][]string{"": {"v1.0.0"}})
+	example := examplePackage(exampleFiles)
+
+	renderer, err := render.NewRenderer(templates, fstest.MapFS{}, []*types.Package{custom, example})
+	if err != nil {
+		t.Fatalf("error creating renderer: %v", err)
+	}
+
+	testCases := []struct {
+		pkg      *types.Package
+		expected string
+	}{
+		{custom, "[custom header|custom content]"},
+		{example, "[default header of example|default content]"},
+	}
+
+	for _, c := range testCases {
+		testCase := c
+		t.Run(testCase.pkg.TargetName, func(t *testing.T) {
+			t.Parallel()
+
+			buffer := bytes.Buffer{}
+			if err := renderer.RenderFile(testCase.pkg, "README.md", &buffer); err != nil {
+				t.Fatalf("error rendering README: %v", err)
+			}
+
+			if actual := strings.TrimSpace(buffer.String()); actual != testCase.expected {
+				t.Errorf("%q (actual) did not match %q (expected)", actual, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestPackageTemplateOverrideError(t *testing.T) {
+	t.Parallel()
+
+	templates := fstest.MapFS{
+		"layout.tmpl":                 templateFile(`{{ define "" }}{{ block "content" . }}{{ end }}{{ end }}`),
+		"package.tmpl":                templateFile(`{{ define "content" }}{{ end }}`),
+		"packages/custom/broken.tmpl": templateFile(`{{ define "content" }}`),
+	}
+
+	custom := packageWithVersions("custom", map[string][]string{"": {"v1.0.0"}})
+
+	_, err := render.NewRenderer(templates, fstest.MapFS{}, []*types.Package{custom})
+	if err == nil || !strings.Contains(err.Error(), `package "custom"`) {
+		t.Errorf("expected error loading the templates of package custom, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	gitPlumbing "github.com/go-git/go-git/v5/plumbing"
+	gitObject "github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+// Compare implements VersionedFileReader on repositoryReader.
+func (r repositoryReader) Compare(from, to string, withPatch func(path string) bool) (types.Comparison, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	fromCommit, _, err := r.commitForVersion(from)
+	if err != nil {
+		return types.Comparison{}, err
+	}
+
+	toCommit, _, err := r.commitForVersion(to)
+	if err != nil {
+		return types.Comparison{}, err
+	}
+
+	commits, err := r.commitsBetween(fromCommit, toCommit)
+	if err != nil {
+		return types.Comparison{}, err
+	}
+
+	files, err := r.changedFiles(fromCommit, toCommit, withPatch)
+	if err != nil {
+		return types.Comparison{}, err
+	}
+
+	return types.Comparison{
+		Commits: commits,
+		Files:   files,
+	}, nil
+}
+
+// commitsBetween returns the commits reachable from `to` but not from `from`, like `git log from..to`.
+func (r repositoryReader) commitsBetween(from, to *gitObject.Commit) ([]types.CommitInfo, error) {
+	reachableFromOld := make(map[gitPlumbing.Hash]bool)
+
+	err := gitObject.NewCommitPreorderIter(from, nil, nil).ForEach(func(c *gitObject.Commit) error {
+		reachableFromOld[c.Hash] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking history of commit %v: %w", from.Hash, err)
+	}
+
+	ret := make([]types.CommitInfo, 0)
+
+	err = gitObject.NewCommitPreorderIter(to, reachableFromOld, nil).ForEach(func(c *gitObject.Commit) error {
+		ret = append(ret, types.CommitInfo{
+			Hash:    c.Hash.String(),
+			Summary: strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)[0],
+			Author:  c.Author.Name,
+			Date:    c.Committer.When,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking history of commit %v: %w", to.Hash, err)
+	}
+
+	sort.SliceStable(ret, func(a, b int) bool {
+		return ret[a].Date.After(ret[b].Date)
+	})
+
+	return ret, nil
+}
+
+func (r repositoryReader) changedFiles(from, to *gitObject.Commit, withPatch func(path string) bool) ([]types.FileChange, error) {
+	fromTree, err := r.repository.TreeObject(from.TreeHash)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving tree for revision '%v': %w", from.Hash, err)
+	}
+
+	toTree, err := r.repository.TreeObject(to.TreeHash)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving tree for revision '%v': %w", to.Hash, err)
+	}
+
+	changes, err := gitObject.DiffTreeWithOptions(context.Background(), fromTree, toTree, gitObject.DefaultDiffTreeOptions)
+	if err != nil {
+		return nil, fmt.Errorf("error comparing trees: %w", err)
+	}
+
+	ret := make([]types.FileChange, 0, len(changes))
+
+	for _, change := range changes {
+		fileChange := types.FileChange{
+			Path:    change.To.Name,
+			OldPath: "",
+			Action:  "modified",
+			Patch:   "",
+		}
+
+		switch {
+		case change.From.Name == "":
+			fileChange.Action = "added"
+		case change.To.Name == "":
+			fileChange.Action = "deleted"
+			fileChange.Path = change.From.Name
+		case change.From.Name != change.To.Name:
+			fileChange.Action = "renamed"
+			fileChange.OldPath = change.From.Name
+		}
+
+		if withPatch != nil && withPatch(fileChange.Path) {
+			patch, err := change.Patch()
+			if err != nil {
+				return nil, fmt.Errorf("error creating patch for file %q: %w", fileChange.Path, err)
+			}
+
+			fileChange.Patch = patch.String()
+		}
+
+		ret = append(ret, fileChange)
+	}
+
+	sort.Slice(ret, func(a, b int) bool {
+		return ret[a].Path < ret[b].Path
+	})
+
+	return ret, nil
+}
@@ -6,7 +6,9 @@ import (
 	"log"
 	"path"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/Masterminds/semver/v3"
 	git "github.com/go-git/go-git/v5"
@@ -14,6 +16,8 @@ import (
 	gitObject "github.com/go-git/go-git/v5/plumbing/object"
 
 	"golang.org/x/mod/modfile"
+
+	"github.com/anexia-it/go.anx.io/pkg/types"
 )
 
 // repositoryReader is an implementation of VersionedFileReader for git repositories.
@@ -21,6 +25,9 @@ type repositoryReader struct {
 	repository    *git.Repository
 	majorVersions map[string][]string
 	versions      map[string]*gitPlumbing.Reference
+
+	// mutex serializes reading from the repository, go-git is not safe for concurrent use.
+	mutex *sync.Mutex
 }
 
 func newRepositoryReader(repo *git.Repository) (*repositoryReader, error) {
@@ -28,6 +35,7 @@ func newRepositoryReader(repo *git.Repository) (*repositoryReader, error) {
 		repository:    repo,
 		versions:      make(map[string]*gitPlumbing.Reference),
 		majorVersions: make(map[string][]string),
+		mutex:         &sync.Mutex{},
 	}
 
 	if err := ret.addTagVersions(); err != nil {
@@ -139,31 +147,21 @@ func (r *repositoryReader) readMajorVersions() error {
 
 // ReadFile implements VersionedFileReader on repositoryReader.
 func (r repositoryReader) ReadFile(path, version string) (string, error) {
-	tag, ok := r.versions[version]
-	if !ok {
-		return "", fmt.Errorf("no tag for version in repository: %w", git.ErrTagNotFound)
-	}
-
-	var commitHash gitPlumbing.Hash
-	if tagObject, err := r.repository.TagObject(tag.Hash()); err != nil {
-		commitHash = tag.Hash()
-	} else {
-		commitHash = tagObject.Target
-	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 
-	commit, err := r.repository.CommitObject(commitHash)
+	tree, err := r.treeForVersion(version)
 	if err != nil {
-		return "", fmt.Errorf("error resolving commit hash '%v' to commit: %w", commitHash, err)
+		return "", err
 	}
 
-	tree, err := r.repository.TreeObject(commit.TreeHash)
+	entry, err := tree.FindEntry(path)
 	if err != nil {
-		return "", fmt.Errorf("error retrieving tree for revision '%v': %w", tag.Hash(), err)
+		return "", fmt.Errorf("cannot find path in given versions tree: %w: %w", types.ErrFileNotFound, err)
 	}
 
-	entry, err := tree.FindEntry(path)
-	if err != nil {
-		return "", fmt.Errorf("cannot find path in given versions tree: %w", err)
+	if !entry.Mode.IsFile() {
+		return "", fmt.Errorf("path %q is not a file: %w", path, types.ErrFileNotFound)
 	}
 
 	file, err := tree.TreeEntryFile(entry)
@@ -179,6 +177,95 @@ func (r repositoryReader) ReadFile(path, version string) (string, error) {
 	return contents, nil
 }
 
+// Files implements VersionedFileReader on repositoryReader.
+func (r repositoryReader) Files(version string) ([]string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	tree, err := r.treeForVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]string, 0)
+
+	err = tree.Files().ForEach(func(file *gitObject.File) error {
+		ret = append(ret, file.Name)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing files of version '%v': %w", version, err)
+	}
+
+	sort.Strings(ret)
+
+	return ret, nil
+}
+
+// VersionInfo implements VersionedFileReader on repositoryReader.
+func (r repositoryReader) VersionInfo(version string) (types.VersionInfo, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	commit, tagObject, err := r.commitForVersion(version)
+	if err != nil {
+		return types.VersionInfo{}, err
+	}
+
+	ret := types.VersionInfo{
+		Commit:     commit.Hash.String(),
+		Date:       commit.Committer.When,
+		CommitDate: commit.Committer.When,
+		TagMessage: "",
+		IsBranch:   r.versions[version].Name().IsBranch(),
+	}
+
+	if tagObject != nil {
+		ret.Date = tagObject.Tagger.When
+		ret.TagMessage = strings.TrimSpace(tagObject.Message)
+	}
+
+	return ret, nil
+}
+
+func (r repositoryReader) treeForVersion(version string) (*gitObject.Tree, error) {
+	commit, _, err := r.commitForVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := r.repository.TreeObject(commit.TreeHash)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving tree for revision '%v': %w", commit.Hash, err)
+	}
+
+	return tree, nil
+}
+
+// commitForVersion resolves the given version to its commit, also returning the tag object for annotated tags.
+func (r repositoryReader) commitForVersion(version string) (*gitObject.Commit, *gitObject.Tag, error) {
+	tag, ok := r.versions[version]
+	if !ok {
+		return nil, nil, fmt.Errorf("no tag for version %q in repository: %w: %w", version, types.ErrVersionNotFound, git.ErrTagNotFound)
+	}
+
+	commitHash := tag.Hash()
+
+	tagObject, err := r.repository.TagObject(tag.Hash())
+	if err == nil {
+		commitHash = tagObject.Target
+	} else {
+		tagObject = nil
+	}
+
+	commit, err := r.repository.CommitObject(commitHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error resolving commit hash '%v' to commit: %w", commitHash, err)
+	}
+
+	return commit, tagObject, nil
+}
+
 func (r repositoryReader) MajorVersions() []string {
 	ret := make([]string, 0, len(r.majorVersions))
 
@@ -27,7 +27,9 @@ func sortVersions(versions []string) {
 		verA, _ := semver.NewVersion(versions[a])
 		verB, _ := semver.NewVersion(versions[b])
 
-		if verA != nil || verB != nil {
+		// versions equal in semver (e.g. differing only in build metadata) are sorted by name below,
+		// to always get the same order
+		if (verA != nil || verB != nil) && (verA == nil || verB == nil || !verA.Equal(verB)) {
 			return compareSemver(verA, verB)
 		}
 
@@ -0,0 +1,614 @@
+package symbols
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/mod/modfile"
+
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+var (
+	// ErrNoModule is returned when a version of a package does not contain a go.mod file.
+	ErrNoModule = errors.New("no go.mod file found")
+
+	errImportCycle  = errors.New("import cycle between modules")
+	errFileNotFound = errors.New("file not found")
+)
+
+var majorVersionRegex = regexp.MustCompile(`^v\d+$`)
+
+// maxIndexes is the number of indexes kept in memory, the least recently used ones are built again when needed.
+const maxIndexes = 32
+
+// Indexer builds and caches an Index for versions of our packages.
+type Indexer struct {
+	packages []*types.Package
+
+	mutex   sync.Mutex
+	fset    *token.FileSet
+	stubs   map[string]*gotypes.Package
+	indexes map[indexKey]*Index
+	owners  map[*gotypes.Package]*moduleBuild
+
+	// recent holds the keys of the built indexes, the least recently used first
+	recent []indexKey
+}
+
+type indexKey struct {
+	pkg     *types.Package
+	version string
+}
+
+// moduleBuild holds the state while type checking the packages of a single module version.
+type moduleBuild struct {
+	indexer *Indexer
+	index   *Index
+
+	files       map[string]string
+	parsed      []*token.File
+	dirs        map[string][]string
+	checked     map[string]*gotypes.Package
+	checking    map[string]bool
+	ids         map[gotypes.Object]string
+	buildConfig build.Context
+}
+
+// NewIndexer creates a new Indexer, resolving imports of go.anx.io paths to the given packages.
+func NewIndexer(packages []*types.Package) *Indexer {
+	return &Indexer{
+		packages: packages,
+		fset:     token.NewFileSet(),
+		stubs:    make(map[string]*gotypes.Package),
+		indexes:  make(map[indexKey]*Index),
+		owners:   make(map[*gotypes.Package]*moduleBuild),
+		recent:   make([]indexKey, 0, maxIndexes+1),
+	}
+}
+
+// Index returns the Index for the given version of the package, building it if it does not exist yet.
+func (i *Indexer) Index(pkg *types.Package, version string) (*Index, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	idx, err := i.index(pkg, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// only evicting here, no index is being built anymore
+	i.evict()
+
+	return idx, nil
+}
+
+func (i *Indexer) index(pkg *types.Package, version string) (*Index, error) {
+	key := indexKey{pkg, version}
+
+	if idx, ok := i.indexes[key]; ok {
+		if idx == nil {
+			return nil, errImportCycle
+		}
+
+		i.touch(key)
+
+		return idx, nil
+	}
+
+	// mark as being built, catching import cycles between modules
+	i.indexes[key] = nil
+
+	idx, err := i.buildIndex(pkg, version)
+	if err != nil {
+		delete(i.indexes, key)
+		return nil, err
+	}
+
+	i.indexes[key] = idx
+	i.touch(key)
+
+	return idx, nil
+}
+
+// touch marks the index with the given key as most recently used.
+func (i *Indexer) touch(key indexKey) {
+	for n, k := range i.recent {
+		if k == key {
+			i.recent = append(i.recent[:n], i.recent[n+1:]...)
+			break
+		}
+	}
+
+	i.recent = append(i.recent, key)
+}
+
+// evict drops the least recently used indexes above maxIndexes, together with the type checked packages and
+// parsed files of their modules. Indexes still referenced by other indexes stay valid, but are not used for new
+// ones anymore.
+func (i *Indexer) evict() {
+	for len(i.recent) > maxIndexes {
+		key := i.recent[0]
+		i.recent = i.recent[1:]
+
+		idx := i.indexes[key]
+		delete(i.indexes, key)
+
+		for pkg, owner := range i.owners {
+			if owner.index != idx {
+				continue
+			}
+
+			for _, file := range owner.parsed {
+				i.fset.RemoveFile(file)
+			}
+
+			// the files are shared by the builds of all packages of the module
+			owner.parsed = nil
+
+			delete(i.owners, pkg)
+		}
+	}
+}
+
+func (i *Indexer) buildIndex(pkg *types.Package, version string) (*Index, error) {
+	goMod, err := pkg.FileReader.ReadFile("go.mod", version)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoModule, err)
+	}
+
+	modulePath := modfile.ModulePath([]byte(goMod))
+	if modulePath == "" {
+		return nil, fmt.Errorf("%w: go.mod does not declare a module path", ErrNoModule)
+	}
+
+	major := path.Base(modulePath)
+	if !majorVersionRegex.MatchString(major) {
+		major = ""
+	}
+
+	mb := moduleBuild{
+		indexer: i,
+		index: &Index{
+			Package:    pkg,
+			ModulePath: modulePath,
+			Major:      major,
+			Version:    version,
+			packages:   make(map[string]*Package),
+			references: make(map[string][]Reference),
+		},
+		files:    make(map[string]string),
+		dirs:     make(map[string][]string),
+		checked:  make(map[string]*gotypes.Package),
+		checking: make(map[string]bool),
+		ids:      make(map[gotypes.Object]string),
+	}
+
+	if err := mb.readFiles(pkg.FileReader, version); err != nil {
+		return nil, err
+	}
+
+	mb.buildConfig = build.Default
+	mb.buildConfig.GOOS = "linux"
+	mb.buildConfig.GOARCH = "amd64"
+	mb.buildConfig.CgoEnabled = true
+	mb.buildConfig.JoinPath = path.Join
+	mb.buildConfig.OpenFile = func(filePath string) (io.ReadCloser, error) {
+		if content, ok := mb.files[filePath]; ok {
+			return io.NopCloser(strings.NewReader(content)), nil
+		}
+
+		return nil, fmt.Errorf("%w: %v", errFileNotFound, filePath)
+	}
+
+	dirs := make([]string, 0, len(mb.dirs))
+	for dir := range mb.dirs {
+		dirs = append(dirs, dir)
+	}
+
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		mb.checkDir(dir)
+	}
+
+	for file := range mb.index.references {
+		refs := mb.index.references[file]
+		sort.Slice(refs, func(a, b int) bool {
+			return refs[a].Offset < refs[b].Offset
+		})
+	}
+
+	return mb.index, nil
+}
+
+// readFiles reads all Go files of the module, excluding directories the go command would ignore
+// and nested modules.
+func (mb *moduleBuild) readFiles(reader types.VersionedFileReader, version string) error {
+	files, err := reader.Files(version)
+	if err != nil {
+		return fmt.Errorf("error listing files: %w", err)
+	}
+
+	for _, file := range SourceFiles(files) {
+		content, err := reader.ReadFile(file, version)
+		if err != nil {
+			return fmt.Errorf("error reading file %q: %w", file, err)
+		}
+
+		mb.files[file] = strings.ReplaceAll(content, "\r\n", "\n")
+
+		dir := path.Dir(file)
+		mb.dirs[dir] = append(mb.dirs[dir], file)
+	}
+
+	return nil
+}
+
+// SourceFiles returns the Go files belonging to the module from the files of a module version, excluding
+// directories the go command ignores and nested modules.
+func SourceFiles(files []string) []string {
+	nestedModules := make([]string, 0)
+
+	for _, file := range files {
+		if path.Base(file) == "go.mod" && file != "go.mod" {
+			nestedModules = append(nestedModules, path.Dir(file)+"/")
+		}
+	}
+
+	ret := make([]string, 0)
+
+	for _, file := range files {
+		if path.Ext(file) == ".go" && !ignoredDirectory(file, nestedModules) {
+			ret = append(ret, file)
+		}
+	}
+
+	return ret
+}
+
+func ignoredDirectory(file string, nestedModules []string) bool {
+	for _, nested := range nestedModules {
+		if strings.HasPrefix(file, nested) {
+			return true
+		}
+	}
+
+	elements := strings.Split(path.Dir(file), "/")
+	for _, element := range elements {
+		if element == "vendor" || element == "testdata" || strings.HasPrefix(element, "_") ||
+			(strings.HasPrefix(element, ".") && element != ".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (mb *moduleBuild) importPath(dir string) string {
+	if dir == "." {
+		return mb.index.ModulePath
+	}
+
+	return mb.index.ModulePath + "/" + dir
+}
+
+// checkDir type checks the package in the given directory, once for the package itself and once for its tests.
+func (mb *moduleBuild) checkDir(dir string) *gotypes.Package {
+	if pkg, ok := mb.checked[dir]; ok || mb.checking[dir] {
+		return pkg
+	}
+
+	mb.checking[dir] = true
+	defer delete(mb.checking, dir)
+
+	var pkgFiles, testFiles, xtestFiles []*ast.File
+
+	for _, file := range mb.dirs[dir] {
+		if match, err := mb.buildConfig.MatchFile(dir, path.Base(file)); err != nil || !match {
+			continue
+		}
+
+		parsed, err := parser.ParseFile(mb.indexer.fset, file, mb.files[file], parser.SkipObjectResolution)
+		if err != nil && parsed == nil {
+			continue
+		}
+
+		mb.parsed = append(mb.parsed, mb.indexer.fset.File(parsed.Pos()))
+
+		switch {
+		case !strings.HasSuffix(file, "_test.go"):
+			pkgFiles = append(pkgFiles, parsed)
+		case strings.HasSuffix(parsed.Name.Name, "_test"):
+			xtestFiles = append(xtestFiles, parsed)
+		default:
+			testFiles = append(testFiles, parsed)
+		}
+	}
+
+	importPath := mb.importPath(dir)
+
+	pkg := mb.check(importPath, pkgFiles, pkgFiles)
+	mb.checked[dir] = pkg
+
+	if pkg != nil {
+		declarations := make(map[string]*Location)
+
+		for _, name := range pkg.Scope().Names() {
+			if location := mb.indexer.locate(pkg.Scope().Lookup(name)); location != nil {
+				declarations[name] = location
+			}
+		}
+
+		mb.index.packages[importPath] = &Package{
+			ImportPath:   importPath,
+			Dir:          dir,
+			Name:         pkg.Name(),
+			Types:        pkg,
+			declarations: declarations,
+		}
+	}
+
+	if len(testFiles) > 0 {
+		mb.check(importPath, append(append([]*ast.File{}, pkgFiles...), testFiles...), testFiles)
+	}
+
+	if len(xtestFiles) > 0 {
+		mb.check(importPath+"_test", xtestFiles, xtestFiles)
+	}
+
+	return pkg
+}
+
+// check type checks the given files as package and records references for the files in recordFiles.
+func (mb *moduleBuild) check(importPath string, files []*ast.File, recordFiles []*ast.File) *gotypes.Package {
+	if len(files) == 0 {
+		return nil
+	}
+
+	//nolint:exhaustruct // we only need those
+	info := gotypes.Info{
+		Defs: make(map[*ast.Ident]gotypes.Object),
+		Uses: make(map[*ast.Ident]gotypes.Object),
+	}
+
+	//nolint:exhaustruct // defaults are fine for everything else
+	config := gotypes.Config{
+		Importer: mb,
+		// we want everything we can get, errors are expected with stubbed imports
+		Error: func(error) {},
+	}
+
+	pkg, _ := config.Check(importPath, mb.indexer.fset, files, &info)
+
+	mb.indexer.owners[pkg] = mb
+	mb.assignIDs(pkg)
+
+	for _, file := range recordFiles {
+		mb.recordReferences(file, &info)
+	}
+
+	return pkg
+}
+
+// Import implements go/types.Importer, resolving packages of this module, other modules
+// on go.anx.io and stubbing everything else.
+func (mb *moduleBuild) Import(importPath string) (*gotypes.Package, error) {
+	if importPath == mb.index.ModulePath || strings.HasPrefix(importPath, mb.index.ModulePath+"/") {
+		dir := strings.TrimPrefix(strings.TrimPrefix(importPath, mb.index.ModulePath), "/")
+		if dir == "" {
+			dir = "."
+		}
+
+		if _, ok := mb.dirs[dir]; ok {
+			if pkg := mb.checkDir(dir); pkg != nil {
+				return pkg, nil
+			}
+		}
+	} else if pkg := mb.indexer.importOtherModule(importPath); pkg != nil {
+		return pkg, nil
+	}
+
+	return mb.indexer.stub(importPath), nil
+}
+
+// importOtherModule resolves import paths of other modules hosted on go.anx.io, using their latest version.
+func (i *Indexer) importOtherModule(importPath string) *gotypes.Package {
+	elements := strings.Split(importPath, "/")
+	if len(elements) < 2 || elements[0] != "go.anx.io" {
+		return nil
+	}
+
+	for _, pkg := range i.packages {
+		if pkg.TargetName != elements[1] || pkg.FileReader == nil {
+			continue
+		}
+
+		major := ""
+		if len(elements) > 2 && majorVersionRegex.MatchString(elements[2]) {
+			major = elements[2]
+		}
+
+		versions := pkg.FileReader.Versions(major)
+		if len(versions) == 0 {
+			return nil
+		}
+
+		idx, err := i.index(pkg, versions[0])
+		if err != nil {
+			return nil
+		}
+
+		if p, ok := idx.packages[importPath]; ok {
+			return p.Types
+		}
+	}
+
+	return nil
+}
+
+// stub returns an empty, complete package for the given import path.
+func (i *Indexer) stub(importPath string) *gotypes.Package {
+	if pkg, ok := i.stubs[importPath]; ok {
+		return pkg
+	}
+
+	pkg := gotypes.NewPackage(importPath, guessPackageName(importPath))
+	pkg.MarkComplete()
+
+	i.stubs[importPath] = pkg
+
+	return pkg
+}
+
+// guessPackageName guesses the name of a package from its import path, following common conventions
+// like `gopkg.in/yaml.v2` -> `yaml`, `github.com/foo/go-bar/v2` -> `bar`.
+func guessPackageName(importPath string) string {
+	elements := strings.Split(importPath, "/")
+	name := elements[len(elements)-1]
+
+	if len(elements) > 1 && majorVersionRegex.MatchString(name) {
+		name = elements[len(elements)-2]
+	}
+
+	if idx := strings.Index(name, ".v"); idx > 0 {
+		name = name[:idx]
+	}
+
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+
+	return strings.NewReplacer("-", "_", ".", "_").Replace(name)
+}
+
+// assignIDs gives anchor IDs to the package level objects of a package, the methods of its types and
+// the fields of its struct types.
+func (mb *moduleBuild) assignIDs(pkg *gotypes.Package) {
+	scope := pkg.Scope()
+
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if name == "_" || name == "init" {
+			continue
+		}
+
+		mb.ids[obj] = name
+
+		typeName, ok := obj.(*gotypes.TypeName)
+		if !ok {
+			continue
+		}
+
+		if named, ok := typeName.Type().(*gotypes.Named); ok {
+			for m := 0; m < named.NumMethods(); m++ {
+				mb.ids[named.Method(m)] = name + "." + named.Method(m).Name()
+			}
+		}
+
+		switch underlying := typeName.Type().Underlying().(type) {
+		case *gotypes.Struct:
+			for f := 0; f < underlying.NumFields(); f++ {
+				mb.ids[underlying.Field(f)] = name + "." + underlying.Field(f).Name()
+			}
+		case *gotypes.Interface:
+			for m := 0; m < underlying.NumExplicitMethods(); m++ {
+				mb.ids[underlying.ExplicitMethod(m)] = name + "." + underlying.ExplicitMethod(m).Name()
+			}
+		}
+	}
+}
+
+func (mb *moduleBuild) recordReferences(file *ast.File, info *gotypes.Info) {
+	tokenFile := mb.indexer.fset.File(file.Pos())
+	fileName := tokenFile.Name()
+
+	refs := mb.index.references[fileName]
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectorExpr:
+			// selectors on stubbed packages are not resolved by go/types, we link them to their documentation
+			if ident, ok := node.X.(*ast.Ident); ok {
+				if pkgName, ok := info.Uses[ident].(*gotypes.PkgName); ok && mb.indexer.isStub(pkgName.Imported()) {
+					refs = append(refs, Reference{
+						Offset: tokenFile.Offset(node.Sel.Pos()),
+						Length: len(node.Sel.Name),
+						Target: &Location{ImportPath: pkgName.Imported().Path(), ID: node.Sel.Name},
+					})
+				}
+			}
+		case *ast.Ident:
+			if obj := info.Defs[node]; obj != nil {
+				if id, ok := mb.ids[obj]; ok {
+					refs = append(refs, Reference{
+						Offset: tokenFile.Offset(node.Pos()),
+						Length: len(node.Name),
+						ID:     id,
+					})
+				}
+			} else if obj := info.Uses[node]; obj != nil {
+				if target := mb.indexer.locate(obj); target != nil {
+					refs = append(refs, Reference{
+						Offset: tokenFile.Offset(node.Pos()),
+						Length: len(node.Name),
+						Target: target,
+					})
+				}
+			}
+		}
+
+		return true
+	})
+
+	mb.index.references[fileName] = refs
+}
+
+func (i *Indexer) isStub(pkg *gotypes.Package) bool {
+	return pkg != nil && i.stubs[pkg.Path()] == pkg
+}
+
+// locate finds the declaration of the given object.
+func (i *Indexer) locate(obj gotypes.Object) *Location {
+	switch o := obj.(type) {
+	case *gotypes.PkgName:
+		if i.isStub(o.Imported()) {
+			return &Location{ImportPath: o.Imported().Path()}
+		}
+
+		return nil
+	case *gotypes.Func:
+		obj = o.Origin()
+	case *gotypes.Var:
+		obj = o.Origin()
+	}
+
+	if obj.Pkg() == nil || !obj.Pos().IsValid() {
+		return nil
+	}
+
+	owner, ok := i.owners[obj.Pkg()]
+	if !ok {
+		return nil
+	}
+
+	position := i.fset.Position(obj.Pos())
+
+	return &Location{
+		Module:     owner.index,
+		ImportPath: obj.Pkg().Path(),
+		File:       position.Filename,
+		Line:       position.Line,
+		ID:         owner.ids[obj],
+	}
+}
@@ -0,0 +1,171 @@
+// Package symbols resolves identifiers in Go code to their declarations, allowing code on
+// the rendered pages to link references to definitions.
+//
+// The sources of a module version are type checked with go/types, the standard library
+// and every import we cannot resolve from our configured packages are replaced with empty
+// stub packages. Type checking errors caused by those stubs are ignored, we only want to
+// know as much as we can find out about the identifiers.
+package symbols
+
+import (
+	"go/scanner"
+	"go/token"
+	gotypes "go/types"
+	"sort"
+
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+// Location describes where an object referenced in code is declared.
+type Location struct {
+	// Module is the Index of the module version declaring the object, nil for objects
+	// declared in packages not hosted on go.anx.io (e.g. the standard library).
+	Module *Index
+
+	// ImportPath is the import path of the package declaring the object.
+	ImportPath string
+
+	// File is the path of the file (relative to the module root) declaring the object,
+	// only set when Module is not nil.
+	File string
+
+	// Line is the line the object is declared on, only set when Module is not nil.
+	Line int
+
+	// ID is the anchor identifier for the object, e.g. `Client` or `Client.Do`. Objects
+	// local to a function do not have an ID, those are only referenced by Line.
+	ID string
+}
+
+// Reference is an identifier in code, either declaring an object with an anchor or
+// referencing an object declared somewhere else.
+type Reference struct {
+	Offset int
+	Length int
+
+	// ID is set when the identifier declares an object that can be linked to.
+	ID string
+
+	// Target is set when the identifier refers to an object.
+	Target *Location
+}
+
+// Package is a single Go package in a module version.
+type Package struct {
+	ImportPath string
+	Dir        string
+	Name       string
+
+	// Types is the type checked package, only including the non-test files.
+	Types *gotypes.Package
+
+	declarations map[string]*Location
+}
+
+// Exported returns the locations of the exported package level declarations of the package, by name.
+func (p *Package) Exported() map[string]*Location {
+	ret := make(map[string]*Location)
+
+	for name, location := range p.declarations {
+		if token.IsExported(name) {
+			ret[name] = location
+		}
+	}
+
+	return ret
+}
+
+// Index holds the packages and references to declarations of a single module version.
+type Index struct {
+	Package    *types.Package
+	ModulePath string
+	Major      string
+	Version    string
+
+	packages   map[string]*Package
+	references map[string][]Reference
+}
+
+// Packages returns all packages in the module version, sorted by import path.
+func (idx *Index) Packages() []*Package {
+	ret := make([]*Package, 0, len(idx.packages))
+
+	for _, pkg := range idx.packages {
+		ret = append(ret, pkg)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ImportPath < ret[j].ImportPath
+	})
+
+	return ret
+}
+
+// References returns the references found in the given file, sorted by offset.
+func (idx *Index) References(file string) []Reference {
+	return idx.references[file]
+}
+
+// SnippetReferences finds references to package level objects of this module in a snippet of
+// Go code, like the examples found in README files. Since snippets usually cannot be type checked,
+// this only looks for selectors with the name of one of our packages, e.g. `client.New`.
+func (idx *Index) SnippetReferences(code string) []Reference {
+	packagesByName := make(map[string]*Package)
+
+	for _, pkg := range idx.Packages() {
+		// Packages() is sorted by import path, preferring packages closer to the module root
+		if _, ok := packagesByName[pkg.Name]; !ok {
+			packagesByName[pkg.Name] = pkg
+		}
+	}
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(code))
+
+	var codeScanner scanner.Scanner
+	codeScanner.Init(file, []byte(code), nil, 0)
+
+	ret := make([]Reference, 0)
+
+	// we look for the token sequence IDENT PERIOD IDENT
+	var previous [2]scannedToken
+
+	for {
+		pos, tok, lit := codeScanner.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		current := scannedToken{file.Offset(pos), tok, lit}
+
+		if current.tok == token.IDENT && previous[1].tok == token.PERIOD && previous[0].tok == token.IDENT {
+			if pkg, ok := packagesByName[previous[0].lit]; ok {
+				if target := idx.locatePackageObject(pkg, current.lit); target != nil {
+					ret = append(ret, Reference{
+						Offset: current.offset,
+						Length: len(current.lit),
+						Target: target,
+					})
+				}
+			}
+		}
+
+		previous[0], previous[1] = previous[1], current
+	}
+
+	return ret
+}
+
+type scannedToken struct {
+	offset int
+	tok    token.Token
+	lit    string
+}
+
+func (idx *Index) locatePackageObject(pkg *Package, name string) *Location {
+	if !token.IsExported(name) {
+		return nil
+	}
+
+	return pkg.declarations[name]
+}
@@ -0,0 +1,473 @@
+package symbols_test
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/anexia-it/go.anx.io/pkg/symbols"
+	"github.com/anexia-it/go.anx.io/pkg/types"
+)
+
+// memoryFileReader serves the given files for every version, listing only v1.0.0.
+type memoryFileReader map[string]string
+
+func (r memoryFileReader) MajorVersions() []string {
+	return []string{""}
+}
+
+func (r memoryFileReader) Versions(major string) []string {
+	if major != "" {
+		return []string{}
+	}
+
+	return []string{"v1.0.0"}
+}
+
+func (r memoryFileReader) ReadFile(path, version string) (string, error) {
+	if content, ok := r[path]; ok {
+		return content, nil
+	}
+
+	return "", fmt.Errorf("%w: %v@%v", types.ErrFileNotFound, path, version)
+}
+
+func (r memoryFileReader) Files(version string) ([]string, error) {
+	ret := make([]string, 0, len(r))
+	for file := range r {
+		ret = append(ret, file)
+	}
+
+	sort.Strings(ret)
+
+	return ret, nil
+}
+
+func (r memoryFileReader) VersionInfo(version string) (types.VersionInfo, error) {
+	return types.VersionInfo{}, nil
+}
+
+func (r memoryFileReader) Compare(from, to string, withPatch func(path string) bool) (types.Comparison, error) {
+	return types.Comparison{}, nil
+}
+
+var examplePackage = &types.Package{
+	TargetName: "example",
+	FileReader: memoryFileReader{
+		"go.mod": "module go.anx.io/example\n",
+		"example.go": `package example
+
+import (
+	"fmt"
+
+	"github.com/foo/go-bar/v2"
+	"go.anx.io/example/client"
+	"go.anx.io/other"
+	"gopkg.in/yaml.v3"
+)
+
+// Greeter greets.
+type Greeter struct {
+	Name string
+}
+
+// Greet greets.
+func (g Greeter) Greet() string {
+	return fmt.Sprintf("hello %v", g.Name)
+}
+
+// New creates a client.
+func New() *client.Client {
+	_, _ = yaml.Marshal(bar.Baz)
+
+	return client.New(other.Value)
+}
+`,
+		"example_test.go": `package example
+
+func newGreeter() Greeter {
+	return Greeter{Name: "test"}
+}
+`,
+		"example_external_test.go": `package example_test
+
+import "go.anx.io/example"
+
+var _ = example.New()
+`,
+		"client/client.go": `package client
+
+// Client is a client.
+type Client struct{}
+
+// New creates a new client.
+func New(value int) *Client {
+	return &Client{}
+}
+`,
+		"testdata/ignored.go":  "package ignored\n\nfunc Ignored() {}\n",
+		"_ignored/ignored.go":  "package ignored\n\nfunc Ignored() {}\n",
+		"nested/go.mod":        "module go.anx.io/example/nested\n",
+		"nested/nested.go":     "package nested\n\nfunc Nested() {}\n",
+		"vendor/foo/vendor.go": "package foo\n\nfunc Vendored() {}\n",
+	},
+}
+
+var otherPackage = &types.Package{
+	TargetName: "other",
+	FileReader: memoryFileReader{
+		"go.mod":   "module go.anx.io/other\n",
+		"other.go": "package other\n\n// Value is a value.\nconst Value = 42\n",
+	},
+}
+
+// cycleAPackage and cycleBPackage import each other, only possible across modules.
+var cycleAPackage = &types.Package{
+	TargetName: "cyclea",
+	FileReader: memoryFileReader{
+		"go.mod": "module go.anx.io/cyclea\n",
+		"a.go":   "package cyclea\n\nimport \"go.anx.io/cycleb\"\n\n// A is a.\nvar A = cycleb.B\n",
+	},
+}
+
+var cycleBPackage = &types.Package{
+	TargetName: "cycleb",
+	FileReader: memoryFileReader{
+		"go.mod": "module go.anx.io/cycleb\n",
+		"b.go":   "package cycleb\n\nimport \"go.anx.io/cyclea\"\n\n// B is b.\nvar B = 1\n\nfunc b() { _ = cyclea.A }\n",
+	},
+}
+
+var noModulePackage = &types.Package{
+	TargetName: "nomodule",
+	FileReader: memoryFileReader{
+		"nomodule.go": "package nomodule\n",
+	},
+}
+
+func newIndexer() *symbols.Indexer {
+	return symbols.NewIndexer([]*types.Package{
+		examplePackage, otherPackage, cycleAPackage, cycleBPackage, noModulePackage,
+	})
+}
+
+func TestReferences(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name string
+		pkg  *types.Package
+		file string
+
+		// needle is a unique part of the file, ident the identifier in it we look at
+		needle string
+		ident  string
+
+		id           string
+		targetModule string
+		targetPath   string
+		targetFile   string
+		targetID     string
+	}
+
+	testCases := []testCase{
+		{
+			name: "type declaration", pkg: examplePackage, file: "example.go",
+			needle: "type Greeter", ident: "Greeter",
+			id: "Greeter", targetModule: "", targetPath: "", targetFile: "", targetID: "",
+		},
+		{
+			name: "field declaration", pkg: examplePackage, file: "example.go",
+			needle: "\tName string", ident: "Name",
+			id: "Greeter.Name", targetModule: "", targetPath: "", targetFile: "", targetID: "",
+		},
+		{
+			name: "method declaration", pkg: examplePackage, file: "example.go",
+			needle: "Greet() string", ident: "Greet",
+			id: "Greeter.Greet", targetModule: "", targetPath: "", targetFile: "", targetID: "",
+		},
+		{
+			name: "field use", pkg: examplePackage, file: "example.go",
+			needle: "g.Name)", ident: "Name",
+			id: "", targetModule: "go.anx.io/example", targetPath: "go.anx.io/example", targetFile: "example.go",
+			targetID: "Greeter.Name",
+		},
+		{
+			name: "standard library package", pkg: examplePackage, file: "example.go",
+			needle: "fmt.Sprintf", ident: "fmt",
+			id: "", targetModule: "", targetPath: "fmt", targetFile: "", targetID: "",
+		},
+		{
+			name: "standard library selector", pkg: examplePackage, file: "example.go",
+			needle: "fmt.Sprintf", ident: "Sprintf",
+			id: "", targetModule: "", targetPath: "fmt", targetFile: "", targetID: "Sprintf",
+		},
+		{
+			name: "guessed package name with version suffix", pkg: examplePackage, file: "example.go",
+			needle: "yaml.Marshal", ident: "Marshal",
+			id: "", targetModule: "", targetPath: "gopkg.in/yaml.v3", targetFile: "", targetID: "Marshal",
+		},
+		{
+			name: "guessed package name with major version", pkg: examplePackage, file: "example.go",
+			needle: "bar.Baz", ident: "Baz",
+			id: "", targetModule: "", targetPath: "github.com/foo/go-bar/v2", targetFile: "", targetID: "Baz",
+		},
+		{
+			name: "package of the same module", pkg: examplePackage, file: "example.go",
+			needle: "client.New(", ident: "New",
+			id: "", targetModule: "go.anx.io/example", targetPath: "go.anx.io/example/client",
+			targetFile: "client/client.go", targetID: "New",
+		},
+		{
+			name: "other module", pkg: examplePackage, file: "example.go",
+			needle: "other.Value", ident: "Value",
+			id: "", targetModule: "go.anx.io/other", targetPath: "go.anx.io/other", targetFile: "other.go",
+			targetID: "Value",
+		},
+		{
+			name: "test package", pkg: examplePackage, file: "example_test.go",
+			needle: "return Greeter", ident: "Greeter",
+			id: "", targetModule: "go.anx.io/example", targetPath: "go.anx.io/example", targetFile: "example.go",
+			targetID: "Greeter",
+		},
+		{
+			name: "external test package", pkg: examplePackage, file: "example_external_test.go",
+			needle: "example.New", ident: "New",
+			id: "", targetModule: "go.anx.io/example", targetPath: "go.anx.io/example", targetFile: "example.go",
+			targetID: "New",
+		},
+		{
+			name: "import cycle resolved", pkg: cycleAPackage, file: "a.go",
+			needle: "cycleb.B", ident: "B",
+			id: "", targetModule: "go.anx.io/cycleb", targetPath: "go.anx.io/cycleb", targetFile: "b.go",
+			targetID: "B",
+		},
+		{
+			name: "import cycle stubbed", pkg: cycleBPackage, file: "b.go",
+			needle: "cyclea.A", ident: "A",
+			id: "", targetModule: "", targetPath: "go.anx.io/cyclea", targetFile: "", targetID: "A",
+		},
+	}
+
+	indexer := newIndexer()
+
+	// the module indexed first is resolved in the cycle, the other one gets a stub
+	for _, pkg := range []*types.Package{examplePackage, cycleAPackage} {
+		if _, err := indexer.Index(pkg, "v1.0.0"); err != nil {
+			t.Fatalf("error indexing %v: %v", pkg.TargetName, err)
+		}
+	}
+
+	for _, c := range testCases {
+		testCase := c
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			idx, err := indexer.Index(testCase.pkg, "v1.0.0")
+			if err != nil {
+				t.Fatalf("error indexing: %v", err)
+			}
+
+			content, _ := testCase.pkg.FileReader.ReadFile(testCase.file, "v1.0.0")
+
+			needleOffset := strings.Index(content, testCase.needle)
+			if needleOffset < 0 {
+				t.Fatalf("needle %q not found in %v", testCase.needle, testCase.file)
+			}
+
+			offset := needleOffset + strings.Index(testCase.needle, testCase.ident)
+
+			var ref *symbols.Reference
+
+			for _, r := range idx.References(testCase.file) {
+				if r.Offset == offset {
+					reference := r
+					ref = &reference
+				}
+			}
+
+			if ref == nil {
+				t.Fatalf("no reference at offset %v (%q)", offset, testCase.needle)
+			}
+
+			if ref.Length != len(testCase.ident) {
+				t.Errorf("reference has length %v, expected %v", ref.Length, len(testCase.ident))
+			}
+
+			if ref.ID != testCase.id {
+				t.Errorf("reference has ID %q, expected %q", ref.ID, testCase.id)
+			}
+
+			checkTarget(t, ref.Target, testCase.targetModule, testCase.targetPath, testCase.targetFile, testCase.targetID)
+		})
+	}
+}
+
+func checkTarget(t *testing.T, target *symbols.Location, module, importPath, file, id string) {
+	t.Helper()
+
+	if importPath == "" {
+		if target != nil {
+			t.Errorf("expected no target, got %+v", target)
+		}
+
+		return
+	}
+
+	if target == nil {
+		t.Fatalf("expected target in %v, got none", importPath)
+	}
+
+	targetModule := ""
+	if target.Module != nil {
+		targetModule = target.Module.ModulePath
+	}
+
+	if targetModule != module || target.ImportPath != importPath || target.File != file || target.ID != id {
+		t.Errorf("target is %q %q %q %q, expected %q %q %q %q",
+			targetModule, target.ImportPath, target.File, target.ID, module, importPath, file, id)
+	}
+}
+
+func TestIgnoredFiles(t *testing.T) {
+	t.Parallel()
+
+	idx, err := newIndexer().Index(examplePackage, "v1.0.0")
+	if err != nil {
+		t.Fatalf("error indexing: %v", err)
+	}
+
+	importPaths := make([]string, 0)
+	for _, pkg := range idx.Packages() {
+		importPaths = append(importPaths, pkg.ImportPath)
+	}
+
+	expected := []string{"go.anx.io/example", "go.anx.io/example/client"}
+	if strings.Join(importPaths, ",") != strings.Join(expected, ",") {
+		t.Errorf("index has packages %v, expected %v", importPaths, expected)
+	}
+
+	for _, file := range []string{"testdata/ignored.go", "_ignored/ignored.go", "nested/nested.go", "vendor/foo/vendor.go"} {
+		if refs := idx.References(file); len(refs) != 0 {
+			t.Errorf("ignored file %v has references %+v", file, refs)
+		}
+	}
+}
+
+func TestSnippetReferences(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name    string
+		snippet string
+
+		// ident is the identifier in the snippet expected to be referenced, empty for no reference
+		ident      string
+		targetPath string
+		targetFile string
+		targetID   string
+	}
+
+	testCases := []testCase{
+		{
+			name: "package level function", snippet: "c := example.New()",
+			ident: "New", targetPath: "go.anx.io/example", targetFile: "example.go", targetID: "New",
+		},
+		{
+			name: "sub package", snippet: "var c *client.Client",
+			ident: "Client", targetPath: "go.anx.io/example/client", targetFile: "client/client.go", targetID: "Client",
+		},
+		{
+			name: "unexported", snippet: "example.newGreeter()",
+			ident: "", targetPath: "", targetFile: "", targetID: "",
+		},
+		{
+			name: "unknown package", snippet: "fmt.Println(other.Value)",
+			ident: "", targetPath: "", targetFile: "", targetID: "",
+		},
+		{
+			name: "not a selector", snippet: "New()",
+			ident: "", targetPath: "", targetFile: "", targetID: "",
+		},
+	}
+
+	idx, err := newIndexer().Index(examplePackage, "v1.0.0")
+	if err != nil {
+		t.Fatalf("error indexing: %v", err)
+	}
+
+	for _, c := range testCases {
+		testCase := c
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			refs := idx.SnippetReferences(testCase.snippet)
+
+			if testCase.ident == "" {
+				if len(refs) != 0 {
+					t.Errorf("expected no references, got %+v", refs)
+				}
+
+				return
+			}
+
+			if len(refs) != 1 {
+				t.Fatalf("expected a single reference, got %+v", refs)
+			}
+
+			if offset := strings.LastIndex(testCase.snippet, testCase.ident); refs[0].Offset != offset ||
+				refs[0].Length != len(testCase.ident) {
+				t.Errorf("reference is at %v+%v, expected %v+%v", refs[0].Offset, refs[0].Length, offset, len(testCase.ident))
+			}
+
+			checkTarget(t, refs[0].Target, "go.anx.io/example", testCase.targetPath, testCase.targetFile, testCase.targetID)
+		})
+	}
+}
+
+func TestIndexWithoutModule(t *testing.T) {
+	t.Parallel()
+
+	if _, err := newIndexer().Index(noModulePackage, "v1.0.0"); !errors.Is(err, symbols.ErrNoModule) {
+		t.Errorf("expected ErrNoModule, got %v", err)
+	}
+}
+
+func TestIndexEviction(t *testing.T) {
+	t.Parallel()
+
+	indexer := newIndexer()
+
+	first, err := indexer.Index(otherPackage, "v1.0.0")
+	if err != nil {
+		t.Fatalf("error indexing: %v", err)
+	}
+
+	if again, _ := indexer.Index(otherPackage, "v1.0.0"); again != first {
+		t.Errorf("expected the index to be cached")
+	}
+
+	for i := 1; i <= 100; i++ {
+		if _, err := indexer.Index(otherPackage, fmt.Sprintf("v1.0.%v", i)); err != nil {
+			t.Fatalf("error indexing: %v", err)
+		}
+	}
+
+	rebuilt, err := indexer.Index(otherPackage, "v1.0.0")
+	if err != nil {
+		t.Fatalf("error indexing: %v", err)
+	}
+
+	if rebuilt == first {
+		t.Errorf("expected the least recently used index to be evicted")
+	}
+
+	refs := rebuilt.References("other.go")
+	if len(refs) != 1 || refs[0].ID != "Value" {
+		t.Errorf("expected the rebuilt index to declare Value, got %+v", refs)
+	}
+}
@@ -1,10 +1,52 @@
 package types
 
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrVersionNotFound is returned by VersionedFileReader for versions not existing in the package.
+	ErrVersionNotFound = errors.New("version not found")
+
+	// ErrFileNotFound is returned by VersionedFileReader for files not existing in the requested version.
+	ErrFileNotFound = errors.New("file not found")
+)
+
 type VersionedFileReader interface {
 	MajorVersions() []string
 	Versions(major string) []string
 
 	ReadFile(path, version string) (string, error)
+
+	// Files lists the paths of all files in the given version, sorted by path.
+	Files(version string) ([]string, error)
+
+	// VersionInfo returns metadata about the given version.
+	VersionInfo(version string) (VersionInfo, error)
+
+	// Compare lists the commits and changed files between two versions, including a unified diff
+	// for every changed file withPatch returns true for.
+	Compare(from, to string, withPatch func(path string) bool) (Comparison, error)
+}
+
+// VersionInfo holds metadata about a single version of a package.
+type VersionInfo struct {
+	// Commit is the hash of the commit the version points to.
+	Commit string
+
+	// Date is the date the version was created, the date of the tag for annotated tags and the
+	// date of the commit otherwise.
+	Date time.Time
+
+	// CommitDate is the date of the commit the version points to.
+	CommitDate time.Time
+
+	// TagMessage is the message of annotated tags, empty for lightweight tags and branches.
+	TagMessage string
+
+	// IsBranch is true if the version is a branch instead of a tag.
+	IsBranch bool
 }
 
 type Package struct {
@@ -12,9 +54,58 @@ type Package struct {
 	TargetName string `yaml:"targetName"`
 	Summary    string `yaml:"summary"`
 
+	// SourceHost selects the URL scheme of links into the source repository, one of the presets of
+	// package forge. Detected from the host of Source if empty.
+	SourceHost string `yaml:"sourceHost"`
+
+	// SourceURLs overrides single URL templates of the SourceHost preset.
+	SourceURLs SourceURLTemplates `yaml:"sourceURLs"`
+
 	// This holds the major versions of the package (v0, v1, v2, ..), the fine versions are retrieved
 	// with FileReader.Versions(majorVersion).
 	Versions []string
 
 	FileReader VersionedFileReader `yaml:"-"`
 }
+
+// SourceURLTemplates are the templates for links into the source repository of a package. The placeholders
+// {repo} (Source without .git suffix), {ref} (the version) and {commit} are replaced by us, {dir}, {/dir},
+// {file} and {line} are kept for consumers of the go-source meta tag and replaced by us when linking files.
+type SourceURLTemplates struct {
+	Directory string `yaml:"directory"`
+	File      string `yaml:"file"`
+	Line      string `yaml:"line"`
+}
+
+// Comparison describes the changes between two versions of a package.
+type Comparison struct {
+	// Commits contains the commits reachable from the newer version but not from the older one,
+	// newest first.
+	Commits []CommitInfo
+
+	// Files contains the changed files, sorted by path.
+	Files []FileChange
+}
+
+// CommitInfo holds metadata about a single commit.
+type CommitInfo struct {
+	Hash    string
+	Summary string
+	Author  string
+	Date    time.Time
+}
+
+// FileChange describes the change of a single file between two versions.
+type FileChange struct {
+	// Path is the path of the file in the newer version, or in the older one for deleted files.
+	Path string
+
+	// OldPath is the path of the file in the older version, only set for renamed files.
+	OldPath string
+
+	// Action is one of "added", "modified", "deleted" or "renamed".
+	Action string
+
+	// Patch is the unified diff of the file, only set if requested for this file.
+	Patch string
+}
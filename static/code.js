@@ -0,0 +1,21 @@
+// Copies the code of a code block to the clipboard when clicking its copy button, without line numbers
+// and diff markers.
+(function() {
+  document.addEventListener("click", (event) => {
+    const button = event.target.closest(".codeBlock .copyCode");
+    if (button === null) {
+      return;
+    }
+
+    const code = button.closest(".codeBlock").querySelector("pre code");
+    const lines = Array.from(code.querySelectorAll(".cl"));
+    const text = lines.length > 0 ? lines.map((line) => line.textContent).join("") : code.textContent;
+
+    navigator.clipboard.writeText(text).then(() => {
+      const label = button.textContent;
+
+      button.textContent = "Copied";
+      setTimeout(() => { button.textContent = label; }, 1500);
+    });
+  });
+})();
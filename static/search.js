@@ -0,0 +1,62 @@
+// Searches the index generated into /search.json, which holds entries for packages,
+// README headings and exported identifiers of the latest versions of every package.
+(function() {
+  const maxResults = 50;
+  const kindOrder = { "package": 0, "symbol": 1, "heading": 2 };
+
+  let index = null;
+
+  function loadIndex() {
+    if (index === null) {
+      index = fetch("/search.json").then((response) => response.json());
+    }
+
+    return index;
+  }
+
+  function search(entries, query) {
+    query = query.trim().toLowerCase();
+    if (query === "") {
+      return [];
+    }
+
+    return entries
+      .filter((entry) => entry.t.toLowerCase().includes(query) || (entry.d || "").toLowerCase().includes(query))
+      .sort((a, b) => {
+        const exactA = a.t.toLowerCase().endsWith(query) ? 0 : 1;
+        const exactB = b.t.toLowerCase().endsWith(query) ? 0 : 1;
+
+        return (exactA - exactB) || (kindOrder[a.k] - kindOrder[b.k]) || a.t.localeCompare(b.t);
+      })
+      .slice(0, maxResults);
+  }
+
+  function render(container, results) {
+    container.replaceChildren(...results.map((entry) => {
+      const article = document.createElement("article");
+      article.className = entry.k;
+
+      const link = document.createElement("a");
+      link.href = entry.u;
+      link.textContent = entry.t;
+      article.appendChild(link);
+
+      const details = document.createElement("span");
+      details.textContent = entry.d ? `${entry.p} - ${entry.d}` : entry.p;
+      article.appendChild(details);
+
+      return article;
+    }));
+  }
+
+  document.addEventListener("DOMContentLoaded", () => {
+    const input = document.getElementById("searchQuery");
+    const container = document.getElementById("searchResults");
+
+    const update = () => loadIndex().then((entries) => render(container, search(entries, input.value)));
+
+    input.value = new URLSearchParams(window.location.search).get("q") || "";
+    input.addEventListener("input", update);
+    update();
+  });
+})();